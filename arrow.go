@@ -0,0 +1,247 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_ogr_arrow.h"
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// ArrowArrayStream is an Arrow C stream interface (struct ArrowArrayStream)
+// allocated in C memory. Pointer can be converted to the stream type of an
+// Arrow implementation, for example
+//
+//	cdata.ImportCRecordReader((*cdata.CArrowArrayStream)(stream.Pointer()), nil)
+//
+// with github.com/apache/arrow/go. The stream must not outlive its layer.
+type ArrowArrayStream struct {
+	cval *C.struct_ArrowArrayStream
+}
+
+// ArrowSchema is an Arrow C data interface schema (struct ArrowSchema)
+// allocated in C memory.
+type ArrowSchema struct {
+	cval *C.struct_ArrowSchema
+}
+
+// ArrowArray is an Arrow C data interface array (struct ArrowArray)
+// allocated in C memory.
+type ArrowArray struct {
+	cval *C.struct_ArrowArray
+}
+
+// NewArrowSchema allocates an empty schema, to be filled by an Arrow
+// implementation exporting through Pointer.
+func NewArrowSchema() ArrowSchema {
+	return ArrowSchema{(*C.struct_ArrowSchema)(C.calloc(1, C.sizeof_struct_ArrowSchema))}
+}
+
+// NewArrowArray allocates an empty array, to be filled by an Arrow
+// implementation exporting through Pointer.
+func NewArrowArray() ArrowArray {
+	return ArrowArray{(*C.struct_ArrowArray)(C.calloc(1, C.sizeof_struct_ArrowArray))}
+}
+
+// arrowError returns the CPL error message following a failed Arrow call.
+func arrowError(operation string) error {
+	if msg := C.GoString(C.CPLGetLastErrorMsg()); msg != "" {
+		return fmt.Errorf("error: %s: %s", operation, msg)
+	}
+	return fmt.Errorf("error: %s failed", operation)
+}
+
+// GetArrowStream returns an Arrow stream reading the features of the layer
+// that match its current filters, as record batches. Geometries are
+// returned as WKB binary columns tagged with the ogc.wkb extension, and the
+// FID as a first column unless INCLUDE_FID=NO is given. Other options, such
+// as MAX_FEATURES_IN_BATCH, are documented for OGR_L_GetArrowStream.
+// Reading from the stream must not be interleaved with other reads of the
+// layer. Requires GDAL 3.6 or newer.
+func (layer Layer) GetArrowStream(options []string) (ArrowArrayStream, error) {
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	// CPL errors are thread local.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	stream := (*C.struct_ArrowArrayStream)(C.calloc(1, C.sizeof_struct_ArrowArrayStream))
+	C.CPLErrorReset()
+	switch C.go_OGR_L_GetArrowStream(layer.cval, stream, (**C.char)(unsafe.Pointer(&opts[0]))) {
+	case 1:
+		return ArrowArrayStream{stream}, nil
+	case -1:
+		C.free(unsafe.Pointer(stream))
+		return ArrowArrayStream{}, fmt.Errorf("error: Arrow streams require GDAL 3.6 or newer")
+	default:
+		C.free(unsafe.Pointer(stream))
+		return ArrowArrayStream{}, arrowError("getting Arrow stream")
+	}
+}
+
+// Pointer returns the address of the underlying struct ArrowArrayStream.
+func (stream ArrowArrayStream) Pointer() unsafe.Pointer {
+	return unsafe.Pointer(stream.cval)
+}
+
+// Schema returns the schema of the record batches of the stream. The
+// schema must be released by the caller.
+func (stream ArrowArrayStream) Schema() (ArrowSchema, error) {
+	schema := NewArrowSchema()
+	if C.go_ArrowArrayStreamGetSchema(stream.cval, schema.cval) != 0 {
+		err := stream.lastError("getting Arrow schema")
+		schema.Release()
+		return ArrowSchema{}, err
+	}
+	return schema, nil
+}
+
+// Next returns the next record batch of the stream, or false once the
+// stream is exhausted. The array must be released by the caller.
+func (stream ArrowArrayStream) Next() (ArrowArray, bool, error) {
+	array := NewArrowArray()
+	if C.go_ArrowArrayStreamGetNext(stream.cval, array.cval) != 0 {
+		err := stream.lastError("reading Arrow batch")
+		array.Release()
+		return ArrowArray{}, false, err
+	}
+	if array.cval.release == nil {
+		array.Release()
+		return ArrowArray{}, false, nil
+	}
+	return array, true, nil
+}
+
+func (stream ArrowArrayStream) lastError(operation string) error {
+	if msg := C.go_ArrowArrayStreamGetLastError(stream.cval); msg != nil {
+		return fmt.Errorf("error: %s: %s", operation, C.GoString(msg))
+	}
+	return fmt.Errorf("error: %s failed", operation)
+}
+
+// Release releases the stream, unless an Arrow implementation importing it
+// already did, and frees its memory.
+func (stream ArrowArrayStream) Release() {
+	C.go_ArrowArrayStreamRelease(stream.cval)
+	C.free(unsafe.Pointer(stream.cval))
+}
+
+// Pointer returns the address of the underlying struct ArrowSchema.
+func (schema ArrowSchema) Pointer() unsafe.Pointer {
+	return unsafe.Pointer(schema.cval)
+}
+
+// Format returns the Arrow format string of the schema, "+s" for the
+// struct describing a record batch.
+func (schema ArrowSchema) Format() string {
+	return C.GoString(schema.cval.format)
+}
+
+// Name returns the field name of the schema.
+func (schema ArrowSchema) Name() string {
+	return C.GoString(schema.cval.name)
+}
+
+// Children returns the child schemas, one per column for a record batch.
+// They are owned by schema.
+func (schema ArrowSchema) Children() []ArrowSchema {
+	count := int(schema.cval.n_children)
+	if count == 0 {
+		return nil
+	}
+	cChildren := unsafe.Slice(schema.cval.children, count)
+	children := make([]ArrowSchema, count)
+	for i, child := range cChildren {
+		children[i] = ArrowSchema{child}
+	}
+	return children
+}
+
+// Release releases the schema, unless it has been moved, and frees its
+// memory.
+func (schema ArrowSchema) Release() {
+	C.go_ArrowSchemaRelease(schema.cval)
+	C.free(unsafe.Pointer(schema.cval))
+}
+
+// Pointer returns the address of the underlying struct ArrowArray.
+func (array ArrowArray) Pointer() unsafe.Pointer {
+	return unsafe.Pointer(array.cval)
+}
+
+// Length returns the number of rows of the array.
+func (array ArrowArray) Length() int64 {
+	return int64(array.cval.length)
+}
+
+// Release releases the array, unless it has been moved, and frees its
+// memory.
+func (array ArrowArray) Release() {
+	C.go_ArrowArrayRelease(array.cval)
+	C.free(unsafe.Pointer(array.cval))
+}
+
+// WriteArrowBatch writes the record batch array, described by the struct
+// schema, as new features of the layer. WKB columns tagged with the ogc.wkb
+// extension, or named by GEOMETRY_NAME, become geometries; FID sets the
+// name of a column holding feature IDs. The array may be moved by GDAL and
+// must still be released by the caller. Requires GDAL 3.8 or newer.
+func (layer Layer) WriteArrowBatch(schema ArrowSchema, array ArrowArray, options []string) error {
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	// CPL errors are thread local.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	C.CPLErrorReset()
+	switch C.go_OGR_L_WriteArrowBatch(layer.cval, schema.cval, array.cval, (**C.char)(unsafe.Pointer(&opts[0]))) {
+	case 1:
+		return nil
+	case -1:
+		return fmt.Errorf("error: writing Arrow batches requires GDAL 3.8 or newer")
+	default:
+		return arrowError("writing Arrow batch")
+	}
+}
+
+// CreateFieldFromArrowSchema creates a layer field from the Arrow field
+// schema, typically a child of a record batch schema. Requires GDAL 3.8 or
+// newer.
+func (layer Layer) CreateFieldFromArrowSchema(schema ArrowSchema, options []string) error {
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	// CPL errors are thread local.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	C.CPLErrorReset()
+	switch C.go_OGR_L_CreateFieldFromArrowSchema(layer.cval, schema.cval, (**C.char)(unsafe.Pointer(&opts[0]))) {
+	case 1:
+		return nil
+	case -1:
+		return fmt.Errorf("error: creating fields from Arrow schemas requires GDAL 3.8 or newer")
+	default:
+		return arrowError(fmt.Sprintf("creating field %q from Arrow schema", schema.Name()))
+	}
+}
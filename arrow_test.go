@@ -0,0 +1,104 @@
+package gdal
+
+import "testing"
+
+func TestLayerGetArrowStream(t *testing.T) {
+	ds, layer := createPointLayer(t, 25)
+	defer ds.Destroy()
+
+	stream, err := layer.GetArrowStream([]string{"INCLUDE_FID=NO", "MAX_FEATURES_IN_BATCH=10"})
+	if err != nil {
+		t.Fatalf("GetArrowStream: %v", err)
+	}
+	defer stream.Release()
+
+	schema, err := stream.Schema()
+	if err != nil {
+		t.Fatalf("Schema: %v", err)
+	}
+	defer schema.Release()
+
+	if format := schema.Format(); format != "+s" {
+		t.Errorf("schema format = %q, want +s", format)
+	}
+	formats := map[string]string{}
+	for _, child := range schema.Children() {
+		formats[child.Name()] = child.Format()
+	}
+	if len(formats) != 2 {
+		t.Fatalf("schema columns = %v, want value and geometry", formats)
+	}
+	if formats["value"] != "i" {
+		t.Errorf("value column format = %q, want i", formats["value"])
+	}
+
+	var batches, rows int64
+	for {
+		array, ok, err := stream.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if !ok {
+			break
+		}
+		batches++
+		rows += array.Length()
+		array.Release()
+	}
+	if rows != 25 {
+		t.Errorf("stream returned %d rows, want 25", rows)
+	}
+	if batches != 3 {
+		t.Errorf("stream returned %d batches, want 3", batches)
+	}
+}
+
+func TestLayerWriteArrowBatch(t *testing.T) {
+	srcDS, src := createPointLayer(t, 5)
+	defer srcDS.Destroy()
+	dstDS, dst := createMemoryVectorLayer(t, "arrowcopy")
+	defer dstDS.Destroy()
+
+	stream, err := src.GetArrowStream([]string{"INCLUDE_FID=NO"})
+	if err != nil {
+		t.Fatalf("GetArrowStream: %v", err)
+	}
+	defer stream.Release()
+
+	schema, err := stream.Schema()
+	if err != nil {
+		t.Fatalf("Schema: %v", err)
+	}
+	defer schema.Release()
+
+	array, ok, err := stream.Next()
+	if err != nil || !ok {
+		t.Fatalf("Next = %v, %v", ok, err)
+	}
+	defer array.Release()
+
+	if VERSION_NUM < 3080000 {
+		if err := dst.WriteArrowBatch(schema, array, nil); err == nil {
+			t.Error("WriteArrowBatch succeeded before GDAL 3.8")
+		}
+		return
+	}
+
+	for _, child := range schema.Children() {
+		if child.Format() == "i" {
+			if err := dst.CreateFieldFromArrowSchema(child, nil); err != nil {
+				t.Fatalf("CreateFieldFromArrowSchema(%s): %v", child.Name(), err)
+			}
+		}
+	}
+	if err := dst.WriteArrowBatch(schema, array, nil); err != nil {
+		t.Fatalf("WriteArrowBatch: %v", err)
+	}
+
+	if count, _ := dst.FeatureCount(true); count != 5 {
+		t.Errorf("FeatureCount = %d, want 5", count)
+	}
+	if got := collectValues(t, dst); len(got) != 5 || got[4] != 4 {
+		t.Errorf("values = %v, want 0..4", got)
+	}
+}
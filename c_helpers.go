@@ -56,6 +56,15 @@ func cDatasetHandleSlicePtr(data []C.GDALDatasetH) *C.GDALDatasetH {
 	return &data[0]
 }
 
+// cRasterBandHandleSlicePtr returns a pointer to the first raster band handle
+// or nil for an empty slice.
+func cRasterBandHandleSlicePtr(data []C.GDALRasterBandH) *C.GDALRasterBandH {
+	if len(data) == 0 {
+		return nil
+	}
+	return &data[0]
+}
+
 // cGIntBigSlicePtr returns a pointer to the first element of data or nil for
 // an empty slice.
 func cGIntBigSlicePtr(data []C.GIntBig) *C.GIntBig {
@@ -0,0 +1,719 @@
+package gdal
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// CalcInput is a named input band of Calc.
+type CalcInput struct {
+	Name string
+	Band RasterBand
+}
+
+// CalcFunc computes one block of output values. inputs holds the block of
+// every input keyed by its name and out has the same length. Output pixels
+// set to NaN are written as the output nodata value when one is set.
+type CalcFunc func(inputs map[string][]float64, out []float64)
+
+// CalcOptions describes a raster calculation.
+type CalcOptions struct {
+	// Inputs lists the named input bands. All of them must share the same grid.
+	Inputs []CalcInput
+	// Expression is evaluated per pixel when Func is nil. It supports the
+	// input names, numbers, + - * / % ^, comparisons, && || !, and the
+	// functions abs, sqrt, exp, log, log10, floor, ceil, round, min, max and
+	// where(cond, a, b). Comparisons and logical operators yield 1 or 0.
+	Expression string
+	// Func computes a block of output values. It takes precedence over
+	// Expression and may be called concurrently.
+	Func CalcFunc
+	// DataType is the output data type, Float32 by default.
+	DataType DataType
+	// NoData is the output nodata value. When set, pixels where any input is
+	// nodata are written as NoData without being computed.
+	NoData *float64
+	// Driver is the output driver short name, MEM by default. Drivers without
+	// create support are written through a MEM dataset and CreateCopy.
+	Driver string
+	// Filename is the output file name, ignored for MEM.
+	Filename string
+	// CreationOptions are passed to the output driver.
+	CreationOptions []string
+	// Workers is the number of blocks computed in parallel, GOMAXPROCS by default.
+	Workers int
+}
+
+// Calc computes a single band raster from the input bands block by block.
+// Blocks are computed in parallel while GDAL reads and writes are serialized.
+// The caller is responsible for closing the returned dataset.
+func Calc(opts CalcOptions, progress ProgressFunc, data interface{}) (Dataset, error) {
+	if len(opts.Inputs) == 0 {
+		return Dataset{}, fmt.Errorf("calc requires at least one input")
+	}
+	names := make([]string, len(opts.Inputs))
+	for i, input := range opts.Inputs {
+		if input.Name == "" {
+			return Dataset{}, fmt.Errorf("calc input %d has no name", i)
+		}
+		for _, name := range names[:i] {
+			if name == input.Name {
+				return Dataset{}, fmt.Errorf("calc input %q is duplicated", input.Name)
+			}
+		}
+		names[i] = input.Name
+	}
+	if err := checkSameGrid(opts.Inputs); err != nil {
+		return Dataset{}, err
+	}
+
+	compute := opts.Func
+	if compute == nil {
+		if opts.Expression == "" {
+			return Dataset{}, fmt.Errorf("calc requires an expression or a function")
+		}
+		eval, err := compileCalcExpression(opts.Expression, names)
+		if err != nil {
+			return Dataset{}, err
+		}
+		compute = expressionCalcFunc(eval, names)
+	}
+
+	dataType := opts.DataType
+	if dataType == Unknown {
+		dataType = Float32
+	}
+	driverName := opts.Driver
+	if driverName == "" {
+		driverName = DriverNameMEM
+	}
+	driver, err := GetDriverByName(driverName)
+	if err != nil {
+		return Dataset{}, err
+	}
+
+	first := opts.Inputs[0].Band
+	xSize, ySize := first.XSize(), first.YSize()
+
+	direct := driverName == DriverNameMEM || driver.MetadataItem(DCAP_CREATE, "") == "YES"
+	target := driver
+	filename, options := opts.Filename, opts.CreationOptions
+	if driverName == DriverNameMEM {
+		filename = ""
+	}
+	if !direct {
+		if target, err = GetDriverByName(DriverNameMEM); err != nil {
+			return Dataset{}, err
+		}
+		filename, options = "", nil
+	}
+
+	out := target.Create(filename, xSize, ySize, 1, dataType, options)
+	if out.cval == nil {
+		return Dataset{}, fmt.Errorf("calc output %q create error", opts.Filename)
+	}
+	if srcDataset := first.GetDataset(); srcDataset.cval != nil {
+		if err := out.SetGeoTransform(srcDataset.GeoTransform()); err != nil {
+			out.Close()
+			return Dataset{}, err
+		}
+		if projection := srcDataset.Projection(); projection != "" {
+			if err := out.SetProjection(projection); err != nil {
+				out.Close()
+				return Dataset{}, err
+			}
+		}
+	}
+	outBand := out.RasterBand(1)
+	if opts.NoData != nil {
+		if err := outBand.SetNoDataValue(*opts.NoData); err != nil {
+			out.Close()
+			return Dataset{}, err
+		}
+	}
+
+	if err := runCalc(opts, compute, outBand, progress, data); err != nil {
+		out.Close()
+		return Dataset{}, err
+	}
+
+	if direct {
+		out.FlushCache()
+		return out, nil
+	}
+	defer out.Close()
+
+	copied := driver.CreateCopy(opts.Filename, out, 0, opts.CreationOptions, nil, nil)
+	if copied.cval == nil {
+		return Dataset{}, fmt.Errorf("calc output %q copy error", opts.Filename)
+	}
+	return copied, nil
+}
+
+func runCalc(opts CalcOptions, compute CalcFunc, outBand RasterBand, progress ProgressFunc, data interface{}) error {
+	first := opts.Inputs[0].Band
+	xSize, ySize := first.XSize(), first.YSize()
+	_, blockY := first.BlockSize()
+	rows := blockY
+	if minRows := (1<<16 + xSize - 1) / xSize; rows < minRows {
+		rows = minRows
+	}
+
+	var blocks []Window
+	for y := 0; y < ySize; y += rows {
+		height := rows
+		if y+height > ySize {
+			height = ySize - y
+		}
+		blocks = append(blocks, Window{YOff: y, XSize: xSize, YSize: height})
+	}
+
+	noData := make([]struct {
+		value float64
+		ok    bool
+	}, len(opts.Inputs))
+	for i, input := range opts.Inputs {
+		noData[i].value, noData[i].ok = input.Band.NoDataValue()
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(blocks) {
+		workers = len(blocks)
+	}
+
+	var (
+		ioMutex  sync.Mutex
+		errMutex sync.Mutex
+		firstErr error
+		done     int
+		wg       sync.WaitGroup
+	)
+	setErr := func(err error) {
+		errMutex.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		errMutex.Unlock()
+	}
+	failed := func() bool {
+		errMutex.Lock()
+		defer errMutex.Unlock()
+		return firstErr != nil
+	}
+
+	queue := make(chan Window)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for window := range queue {
+				if failed() {
+					continue
+				}
+				size := window.XSize * window.YSize
+				inputs := make(map[string][]float64, len(opts.Inputs))
+				buffers := make([][]float64, len(opts.Inputs))
+
+				ioMutex.Lock()
+				var err error
+				for i, input := range opts.Inputs {
+					buffers[i] = make([]float64, size)
+					err = input.Band.IO(Read, window.XOff, window.YOff, window.XSize, window.YSize,
+						buffers[i], window.XSize, window.YSize, 0, 0)
+					if err != nil {
+						break
+					}
+					inputs[input.Name] = buffers[i]
+				}
+				ioMutex.Unlock()
+				if err != nil {
+					setErr(err)
+					continue
+				}
+
+				out := make([]float64, size)
+				compute(inputs, out)
+
+				if opts.NoData != nil {
+					for p := range out {
+						if math.IsNaN(out[p]) {
+							out[p] = *opts.NoData
+							continue
+						}
+						for i := range buffers {
+							if noData[i].ok && isNoData(buffers[i][p], noData[i].value) {
+								out[p] = *opts.NoData
+								break
+							}
+						}
+					}
+				}
+
+				ioMutex.Lock()
+				err = outBand.IO(Write, window.XOff, window.YOff, window.XSize, window.YSize,
+					out, window.XSize, window.YSize, 0, 0)
+				if err == nil {
+					done++
+					if progress != nil && progress(float64(done)/float64(len(blocks)), "", data) == 0 {
+						err = fmt.Errorf("calc interrupted")
+					}
+				}
+				ioMutex.Unlock()
+				if err != nil {
+					setErr(err)
+				}
+			}
+		}()
+	}
+	for _, window := range blocks {
+		queue <- window
+	}
+	close(queue)
+	wg.Wait()
+
+	return firstErr
+}
+
+// checkSameGrid verifies that all inputs have the same size, geotransform
+// and projection.
+func checkSameGrid(inputs []CalcInput) error {
+	first := inputs[0]
+	firstDataset := first.Band.GetDataset()
+	for _, input := range inputs[1:] {
+		if input.Band.XSize() != first.Band.XSize() || input.Band.YSize() != first.Band.YSize() {
+			return fmt.Errorf("calc input %q size %dx%d differs from %q size %dx%d",
+				input.Name, input.Band.XSize(), input.Band.YSize(),
+				first.Name, first.Band.XSize(), first.Band.YSize())
+		}
+		dataset := input.Band.GetDataset()
+		if dataset.cval == nil || firstDataset.cval == nil {
+			continue
+		}
+		gt, firstGT := dataset.GeoTransform(), firstDataset.GeoTransform()
+		for i := range gt {
+			if math.Abs(gt[i]-firstGT[i]) > 1e-9*math.Max(1, math.Abs(firstGT[i])) {
+				return fmt.Errorf("calc input %q geotransform differs from %q", input.Name, first.Name)
+			}
+		}
+		if dataset.Projection() != firstDataset.Projection() {
+			return fmt.Errorf("calc input %q projection differs from %q", input.Name, first.Name)
+		}
+	}
+	return nil
+}
+
+func expressionCalcFunc(eval func([]float64) float64, names []string) CalcFunc {
+	return func(inputs map[string][]float64, out []float64) {
+		buffers := make([][]float64, len(names))
+		for i, name := range names {
+			buffers[i] = inputs[name]
+		}
+		vars := make([]float64, len(names))
+		for p := range out {
+			for i := range buffers {
+				vars[i] = buffers[i][p]
+			}
+			out[p] = eval(vars)
+		}
+	}
+}
+
+// CalcVRT returns a virtual dataset whose single band is computed on the fly
+// by a GDAL built-in pixel function such as "sum", "diff", "mul", "div" or
+// "scale". The input bands must belong to file backed datasets, including
+// /vsimem/ ones. The caller is responsible for closing the returned dataset.
+func CalcVRT(
+	inputs []RasterBand,
+	pixelFunction string,
+	arguments map[string]string,
+	dataType DataType,
+) (Dataset, error) {
+	if len(inputs) == 0 {
+		return Dataset{}, fmt.Errorf("calc requires at least one input")
+	}
+	calcInputs := make([]CalcInput, len(inputs))
+	for i, band := range inputs {
+		calcInputs[i] = CalcInput{Name: strconv.Itoa(i + 1), Band: band}
+	}
+	if err := checkSameGrid(calcInputs); err != nil {
+		return Dataset{}, err
+	}
+	if dataType == Unknown {
+		dataType = Float32
+	}
+
+	first := inputs[0]
+	builder := NewVRTBuilder(first.XSize(), first.YSize())
+	if dataset := first.GetDataset(); dataset.cval != nil {
+		builder = NewVRTBuilderFromDataset(dataset)
+	}
+	band := builder.AddDerivedBand(dataType, pixelFunction, arguments)
+	band.SourceTransferType = Float64
+	for _, input := range inputs {
+		if _, err := band.AddSimpleSource(input, Window{}, Window{}); err != nil {
+			return Dataset{}, err
+		}
+	}
+	return builder.Build()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// calcNode is a compiled expression node evaluated against the input values.
+type calcNode func(vars []float64) float64
+
+type calcParser struct {
+	tokens []string
+	pos    int
+	names  map[string]int
+}
+
+// compileCalcExpression compiles expr into a function of the input values
+// given in the order of names.
+func compileCalcExpression(expr string, names []string) (func([]float64) float64, error) {
+	tokens, err := tokenizeCalcExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &calcParser{tokens: tokens, names: make(map[string]int, len(names))}
+	for i, name := range names {
+		p.names[name] = i
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("calc expression: unexpected %q", p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+func tokenizeCalcExpression(expr string) ([]string, error) {
+	var tokens []string
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			if j < len(runes) && (runes[j] == 'e' || runes[j] == 'E') {
+				k := j + 1
+				if k < len(runes) && (runes[k] == '+' || runes[k] == '-') {
+					k++
+				}
+				if k < len(runes) && unicode.IsDigit(runes[k]) {
+					for k < len(runes) && unicode.IsDigit(runes[k]) {
+						k++
+					}
+					j = k
+				}
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "==", "!=", "&&", "||":
+					tokens = append(tokens, two)
+					i += 2
+					continue
+				}
+			}
+			if !strings.ContainsRune("+-*/%^()<>!,", r) {
+				return nil, fmt.Errorf("calc expression: unexpected character %q", r)
+			}
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func (p *calcParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *calcParser) accept(token string) bool {
+	if p.peek() == token {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (p *calcParser) parseOr() (calcNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v []float64) float64 { return boolToFloat(l(v) != 0 || right(v) != 0) }
+	}
+	return left, nil
+}
+
+func (p *calcParser) parseAnd() (calcNode, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v []float64) float64 { return boolToFloat(l(v) != 0 && right(v) != 0) }
+	}
+	return left, nil
+}
+
+func (p *calcParser) parseComparison() (calcNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		var cmp func(a, b float64) bool
+		switch op {
+		case "<":
+			cmp = func(a, b float64) bool { return a < b }
+		case "<=":
+			cmp = func(a, b float64) bool { return a <= b }
+		case ">":
+			cmp = func(a, b float64) bool { return a > b }
+		case ">=":
+			cmp = func(a, b float64) bool { return a >= b }
+		case "==":
+			cmp = func(a, b float64) bool { return a == b }
+		case "!=":
+			cmp = func(a, b float64) bool { return a != b }
+		default:
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v []float64) float64 { return boolToFloat(cmp(l(v), right(v))) }
+	}
+}
+
+func (p *calcParser) parseAdditive() (calcNode, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != "+" && op != "-" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "+" {
+			left = func(v []float64) float64 { return l(v) + right(v) }
+		} else {
+			left = func(v []float64) float64 { return l(v) - right(v) }
+		}
+	}
+}
+
+func (p *calcParser) parseMultiplicative() (calcNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op != "*" && op != "/" && op != "%" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		switch op {
+		case "*":
+			left = func(v []float64) float64 { return l(v) * right(v) }
+		case "/":
+			left = func(v []float64) float64 { return l(v) / right(v) }
+		default:
+			left = func(v []float64) float64 { return math.Mod(l(v), right(v)) }
+		}
+	}
+}
+
+func (p *calcParser) parseUnary() (calcNode, error) {
+	switch {
+	case p.accept("-"):
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(v []float64) float64 { return -operand(v) }, nil
+	case p.accept("+"):
+		return p.parseUnary()
+	case p.accept("!"):
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(v []float64) float64 { return boolToFloat(operand(v) == 0) }, nil
+	}
+	return p.parsePower()
+}
+
+func (p *calcParser) parsePower() (calcNode, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("^") {
+		return base, nil
+	}
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return func(v []float64) float64 { return math.Pow(base(v), exponent(v)) }, nil
+}
+
+var calcFunctions = map[string]struct {
+	args int
+	fn   func(args []float64) float64
+}{
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"exp":   {1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"log":   {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"log10": {1, func(a []float64) float64 { return math.Log10(a[0]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {1, func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"round": {1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+	"where": {3, func(a []float64) float64 {
+		if a[0] != 0 {
+			return a[1]
+		}
+		return a[2]
+	}},
+}
+
+func (p *calcParser) parsePrimary() (calcNode, error) {
+	token := p.peek()
+	if token == "" {
+		return nil, fmt.Errorf("calc expression: unexpected end")
+	}
+	p.pos++
+
+	if token == "(" {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("calc expression: missing )")
+		}
+		return node, nil
+	}
+
+	if r := []rune(token)[0]; unicode.IsDigit(r) || r == '.' {
+		value, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, fmt.Errorf("calc expression: invalid number %q", token)
+		}
+		return func([]float64) float64 { return value }, nil
+	}
+
+	if !unicode.IsLetter([]rune(token)[0]) && token[0] != '_' {
+		return nil, fmt.Errorf("calc expression: unexpected %q", token)
+	}
+
+	if p.accept("(") {
+		function, ok := calcFunctions[token]
+		if !ok {
+			return nil, fmt.Errorf("calc expression: unknown function %q", token)
+		}
+		var args []calcNode
+		if !p.accept(")") {
+			for {
+				arg, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if p.accept(")") {
+					break
+				}
+				if !p.accept(",") {
+					return nil, fmt.Errorf("calc expression: expected , or ) in %s()", token)
+				}
+			}
+		}
+		if len(args) != function.args {
+			return nil, fmt.Errorf("calc expression: %s() takes %d arguments, got %d", token, function.args, len(args))
+		}
+		return func(v []float64) float64 {
+			values := make([]float64, len(args))
+			for i, arg := range args {
+				values[i] = arg(v)
+			}
+			return function.fn(values)
+		}, nil
+	}
+
+	index, ok := p.names[token]
+	if !ok {
+		return nil, fmt.Errorf("calc expression: unknown input %q", token)
+	}
+	return func(v []float64) float64 { return v[index] }, nil
+}
@@ -0,0 +1,182 @@
+package gdal
+
+import (
+	"math"
+	"os"
+	"testing"
+)
+
+func createCalcInput(t *testing.T, name string, values []float64, noData *float64) (Dataset, CalcInput) {
+	t.Helper()
+
+	ds := createMemoryRasterDataset(t, len(values), 1, 1, Float64)
+	band := ds.RasterBand(1)
+	if err := band.IO(Write, 0, 0, len(values), 1, values, len(values), 1, 0, 0); err != nil {
+		ds.Close()
+		t.Fatalf("RasterBand.IO(Write): %v", err)
+	}
+	if noData != nil {
+		if err := band.SetNoDataValue(*noData); err != nil {
+			ds.Close()
+			t.Fatalf("SetNoDataValue: %v", err)
+		}
+	}
+	return ds, CalcInput{Name: name, Band: band}
+}
+
+func readCalcOutput(t *testing.T, ds Dataset) []float64 {
+	t.Helper()
+
+	band := ds.RasterBand(1)
+	values := make([]float64, band.XSize()*band.YSize())
+	if err := band.IO(Read, 0, 0, band.XSize(), band.YSize(), values, band.XSize(), band.YSize(), 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Read): %v", err)
+	}
+	return values
+}
+
+func TestCalcExpressionWithNoDataPropagation(t *testing.T) {
+	noData := -1.0
+	nirDS, nir := createCalcInput(t, "nir", []float64{8, 6, -1, 0}, &noData)
+	defer nirDS.Close()
+	redDS, red := createCalcInput(t, "red", []float64{2, 2, 1, 0}, nil)
+	defer redDS.Close()
+
+	outNoData := -9999.0
+	out, err := Calc(CalcOptions{
+		Inputs:     []CalcInput{nir, red},
+		Expression: "(nir - red) / (nir + red)",
+		DataType:   Float64,
+		NoData:     &outNoData,
+	}, DummyProgress, nil)
+	if err != nil {
+		t.Fatalf("Calc: %v", err)
+	}
+	defer out.Close()
+
+	want := []float64{0.6, 0.5, outNoData, outNoData}
+	got := readCalcOutput(t, out)
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("pixel %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if value, ok := out.RasterBand(1).NoDataValue(); !ok || value != outNoData {
+		t.Errorf("NoDataValue() = (%v, %v), want (%v, true)", value, ok, outNoData)
+	}
+}
+
+func TestCalcFuncWritesGTiff(t *testing.T) {
+	filename := "./tmp/calc.tif"
+	defer os.Remove(filename)
+
+	inDS, in := createCalcInput(t, "a", []float64{1, 5, 10}, nil)
+	defer inDS.Close()
+
+	out, err := Calc(CalcOptions{
+		Inputs: []CalcInput{in},
+		Func: func(inputs map[string][]float64, out []float64) {
+			for i, value := range inputs["a"] {
+				if value > 4 {
+					out[i] = 1
+				}
+			}
+		},
+		DataType: Byte,
+		Driver:   DriverNameGTiff,
+		Filename: filename,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Calc: %v", err)
+	}
+	defer out.Close()
+
+	if got := out.RasterBand(1).RasterDataType(); got != Byte {
+		t.Errorf("RasterDataType() = %v, want Byte", got)
+	}
+	got := readCalcOutput(t, out)
+	if got[0] != 0 || got[1] != 1 || got[2] != 1 {
+		t.Errorf("output = %v, want [0 1 1]", got)
+	}
+}
+
+func TestCalcRejectsInvalidInputs(t *testing.T) {
+	aDS, a := createCalcInput(t, "a", []float64{1, 2}, nil)
+	defer aDS.Close()
+	bDS, b := createCalcInput(t, "b", []float64{1, 2, 3}, nil)
+	defer bDS.Close()
+
+	tests := map[string]CalcOptions{
+		"no inputs":     {Expression: "1"},
+		"size mismatch": {Inputs: []CalcInput{a, b}, Expression: "a + b"},
+		"no expression": {Inputs: []CalcInput{a}},
+		"unknown input": {Inputs: []CalcInput{a}, Expression: "a + c"},
+		"bad syntax":    {Inputs: []CalcInput{a}, Expression: "a +"},
+		"duplicate":     {Inputs: []CalcInput{a, a}, Expression: "a"},
+	}
+	for name, opts := range tests {
+		if ds, err := Calc(opts, nil, nil); err == nil {
+			ds.Close()
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestCompileCalcExpression(t *testing.T) {
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"a + b * 2", 5},
+		{"-a ^ 2", -9},
+		{"2 ^ 3 ^ 2", 512},
+		{"where(a > b && !(b == 0), a, b)", 3},
+		{"max(a, b) % 2", 1},
+		{"sqrt(abs(-16)) + 1e1", 14},
+		{"a >= 3 || b < 0", 1},
+	}
+	for _, tt := range tests {
+		eval, err := compileCalcExpression(tt.expr, []string{"a", "b"})
+		if err != nil {
+			t.Errorf("compileCalcExpression(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := eval([]float64{3, 1}); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%q = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "a b", "(a", "min(a)", "a $ b"} {
+		if _, err := compileCalcExpression(expr, []string{"a"}); err == nil {
+			t.Errorf("compileCalcExpression(%q): expected error", expr)
+		}
+	}
+}
+
+func TestCalcVRT(t *testing.T) {
+	filename := "./tmp/calc_vrt_source.tif"
+	defer os.Remove(filename)
+
+	src := createGTiffRasterDataset(t, filename, 4, 4, 2)
+	defer src.Close()
+
+	vrt, err := CalcVRT([]RasterBand{src.RasterBand(1), src.RasterBand(2)}, "sum", nil, Float32)
+	if err != nil {
+		t.Fatalf("CalcVRT: %v", err)
+	}
+	defer vrt.Close()
+
+	got := readCalcOutput(t, vrt)
+	for i, value := range got {
+		if want := float64(2 * (i % 251)); value != want {
+			t.Errorf("pixel %d = %v, want %v", i, value, want)
+		}
+	}
+
+	mem := createMemoryRasterDataset(t, 4, 4, 1, Byte)
+	defer mem.Close()
+	if ds, err := CalcVRT([]RasterBand{mem.RasterBand(1)}, "sum", nil, Float32); err == nil {
+		ds.Close()
+		t.Error("expected error for memory input")
+	}
+}
@@ -0,0 +1,381 @@
+package gdal
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// COGCompression is the compression method of a Cloud Optimized GeoTIFF.
+type COGCompression string
+
+const (
+	COGCompressionNone        = COGCompression("NONE")
+	COGCompressionLZW         = COGCompression("LZW")
+	COGCompressionDeflate     = COGCompression("DEFLATE")
+	COGCompressionZSTD        = COGCompression("ZSTD")
+	COGCompressionJPEG        = COGCompression("JPEG")
+	COGCompressionWEBP        = COGCompression("WEBP")
+	COGCompressionLERC        = COGCompression("LERC")
+	COGCompressionLERCDeflate = COGCompression("LERC_DEFLATE")
+	COGCompressionLERCZSTD    = COGCompression("LERC_ZSTD")
+)
+
+// COGPredictor is the predictor used with LZW, DEFLATE and ZSTD compression.
+type COGPredictor string
+
+const (
+	COGPredictorNone          = COGPredictor("NO")
+	COGPredictorAuto          = COGPredictor("YES")
+	COGPredictorStandard      = COGPredictor("STANDARD")
+	COGPredictorFloatingPoint = COGPredictor("FLOATING_POINT")
+)
+
+// COGTilingScheme is the tiling scheme of a Cloud Optimized GeoTIFF.
+type COGTilingScheme string
+
+const (
+	COGTilingSchemeCustom               = COGTilingScheme("CUSTOM")
+	COGTilingSchemeGoogleMapsCompatible = COGTilingScheme("GoogleMapsCompatible")
+)
+
+// COGOptions describes how WriteCOG creates a Cloud Optimized GeoTIFF. Zero
+// values leave the corresponding COG driver option at its default.
+type COGOptions struct {
+	Compression COGCompression
+	// Level is the compression level of DEFLATE, ZSTD and LERC variants.
+	Level int
+	// Quality is the JPEG or WEBP quality, between 1 and 100.
+	Quality int
+	// BlockSize is the tile size in pixels, a multiple of 16.
+	BlockSize int
+	Predictor COGPredictor
+	// OverviewResampling is the resampling method of the overviews, e.g.
+	// "AVERAGE" or "NEAREST".
+	OverviewResampling string
+	// Overviews is "AUTO", "IGNORE_EXISTING", "FORCE_USE_EXISTING" or "NONE".
+	Overviews string
+	// TargetSRS reprojects the source, e.g. "EPSG:3857".
+	TargetSRS string
+	// Resampling is the resampling method used when reprojecting.
+	Resampling   string
+	TilingScheme COGTilingScheme
+	// BigTIFF is "YES", "NO", "IF_NEEDED" or "IF_SAFER".
+	BigTIFF string
+	// NumThreads is a thread count or "ALL_CPUS".
+	NumThreads string
+	// CreationOptions holds additional NAME=VALUE driver options.
+	CreationOptions []string
+}
+
+// creationOptions returns the COG driver creation options for opts.
+func (opts COGOptions) creationOptions() ([]string, error) {
+	if opts.BlockSize < 0 || opts.BlockSize%16 != 0 {
+		return nil, fmt.Errorf("error: block size %d must be a positive multiple of 16", opts.BlockSize)
+	}
+	if opts.Quality < 0 || opts.Quality > 100 {
+		return nil, fmt.Errorf("error: quality %d must be between 1 and 100", opts.Quality)
+	}
+	if opts.Level < 0 {
+		return nil, fmt.Errorf("error: compression level %d must not be negative", opts.Level)
+	}
+
+	var options []string
+	add := func(name, value string) {
+		if value != "" {
+			options = append(options, name+"="+value)
+		}
+	}
+	add("COMPRESS", string(opts.Compression))
+	if opts.Level > 0 {
+		add("LEVEL", strconv.Itoa(opts.Level))
+	}
+	if opts.Quality > 0 {
+		add("QUALITY", strconv.Itoa(opts.Quality))
+	}
+	if opts.BlockSize > 0 {
+		add("BLOCKSIZE", strconv.Itoa(opts.BlockSize))
+	}
+	add("PREDICTOR", string(opts.Predictor))
+	add("OVERVIEW_RESAMPLING", opts.OverviewResampling)
+	add("OVERVIEWS", opts.Overviews)
+	add("TARGET_SRS", opts.TargetSRS)
+	add("RESAMPLING", opts.Resampling)
+	add("TILING_SCHEME", string(opts.TilingScheme))
+	add("BIGTIFF", opts.BigTIFF)
+	add("NUM_THREADS", opts.NumThreads)
+	return append(options, opts.CreationOptions...), nil
+}
+
+// WriteCOG writes src to dst as a Cloud Optimized GeoTIFF using the COG
+// driver.
+func WriteCOG(src Dataset, dst string, opts COGOptions, progress ProgressFunc, data interface{}) error {
+	options, err := opts.creationOptions()
+	if err != nil {
+		return err
+	}
+
+	driver, err := GetDriverByName(DriverNameCOG)
+	if err != nil {
+		return err
+	}
+
+	ds := driver.CreateCopy(dst, src, 0, options, progress, data)
+	if ds.cval == nil {
+		return fmt.Errorf("error: failed to write COG %q", dst)
+	}
+	ds.Close()
+	return nil
+}
+
+// COGSeverity is the severity of a COGFinding.
+type COGSeverity int
+
+const (
+	// COGWarning marks a finding that does not break the COG layout but is
+	// not recommended.
+	COGWarning COGSeverity = iota
+	// COGError marks a finding that makes the file not cloud optimized.
+	COGError
+)
+
+// String implements fmt.Stringer.
+func (severity COGSeverity) String() string {
+	if severity == COGError {
+		return "error"
+	}
+	return "warning"
+}
+
+// COGFinding is a single result of ValidateCOG.
+type COGFinding struct {
+	Severity COGSeverity
+	Message  string
+}
+
+// COGValidation holds the results of ValidateCOG.
+type COGValidation struct {
+	Findings []COGFinding
+	// IFDOffsets lists the IFD offsets of the main image, then of each overview.
+	IFDOffsets []int64
+	// DataOffsets lists the offset of the first block of the main image, then
+	// of each overview. An offset of 0 means the image has no block written.
+	DataOffsets []int64
+	// StructuralMetadata holds the GDAL structural metadata found after the
+	// TIFF header, e.g. LAYOUT=COG or BLOCK_ORDER=ROW_MAJOR.
+	StructuralMetadata map[string]string
+}
+
+// Valid reports whether no error was found.
+func (validation COGValidation) Valid() bool {
+	return len(validation.Errors()) == 0
+}
+
+// Errors returns the messages of the error findings.
+func (validation COGValidation) Errors() []string {
+	return validation.messages(COGError)
+}
+
+// Warnings returns the messages of the warning findings.
+func (validation COGValidation) Warnings() []string {
+	return validation.messages(COGWarning)
+}
+
+func (validation COGValidation) messages(severity COGSeverity) []string {
+	var messages []string
+	for _, finding := range validation.Findings {
+		if finding.Severity == severity {
+			messages = append(messages, finding.Message)
+		}
+	}
+	return messages
+}
+
+func (validation *COGValidation) add(severity COGSeverity, format string, args ...interface{}) {
+	validation.Findings = append(validation.Findings, COGFinding{
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+const cogStructuralMetadataKey = "GDAL_STRUCTURAL_METADATA_SIZE="
+
+// ValidateCOG checks that the GeoTIFF at path follows the Cloud Optimized
+// GeoTIFF layout: tiled images, internal overviews of decreasing size, IFDs
+// at the start of the file in order, and image data stored from the smallest
+// overview to the full resolution image. It mirrors the checks of GDAL's
+// validate_cloud_optimized_geotiff.py script. An error is returned only when
+// the file cannot be opened or is not a GeoTIFF.
+func ValidateCOG(path string) (COGValidation, error) {
+	var validation COGValidation
+
+	ds, err := OpenEx(path, OFReadOnly|OFRaster, []string{DriverNameGTiff}, nil, nil)
+	if err != nil {
+		return validation, fmt.Errorf("error: %q is not a GeoTIFF: %v", path, err)
+	}
+	defer ds.Close()
+
+	if ds.RasterCount() == 0 {
+		return validation, fmt.Errorf("error: %q has no raster band", path)
+	}
+	mainBand := ds.RasterBand(1)
+	overviews := mainBand.Overviews()
+
+	for _, file := range ds.FileList() {
+		if strings.EqualFold(file, path+".ovr") {
+			validation.add(COGError, "overviews found in external .ovr file, they should be internal")
+		}
+	}
+
+	if mainBand.XSize() > 512 || mainBand.YSize() > 512 {
+		if blockXSize, _ := mainBand.BlockSize(); blockXSize == mainBand.XSize() && blockXSize > 1024 {
+			validation.add(COGError, "the file is greater than 512xH or Wx512, but is not tiled")
+		}
+		if len(overviews) == 0 {
+			validation.add(COGWarning, "the file is greater than 512xH or Wx512, it is recommended to include internal overviews")
+		}
+	}
+
+	ifdOffset, err := cogOffset(mainBand, "IFD_OFFSET")
+	if err != nil {
+		return validation, err
+	}
+	validation.IFDOffsets = append(validation.IFDOffsets, ifdOffset)
+
+	expected, metadata := cogExpectedIFDOffset(path)
+	validation.StructuralMetadata = metadata
+	if ifdOffset != expected {
+		validation.add(COGError, "the offset of the main IFD should be %d, it is %d instead", expected, ifdOffset)
+	}
+
+	for i, overview := range overviews {
+		previous, previousName := mainBand, "main band"
+		if i > 0 {
+			previous, previousName = overviews[i-1], fmt.Sprintf("overview of index %d", i-1)
+		}
+		if overview.XSize() > previous.XSize() || overview.YSize() > previous.YSize() {
+			validation.add(COGError, "overview of index %d has larger dimension than %s", i, previousName)
+		}
+		if blockXSize, _ := overview.BlockSize(); blockXSize == overview.XSize() && blockXSize > 1024 {
+			validation.add(COGError, "overview of index %d is not tiled", i)
+		}
+
+		offset, err := cogOffset(overview, "IFD_OFFSET")
+		if err != nil {
+			return validation, err
+		}
+		previousOffset := validation.IFDOffsets[len(validation.IFDOffsets)-1]
+		validation.IFDOffsets = append(validation.IFDOffsets, offset)
+		if offset < previousOffset {
+			validation.add(COGError,
+				"the offset of the IFD for overview of index %d is %d, whereas it should be greater than the one of the %s, which is at byte %d",
+				i, offset, previousName, previousOffset)
+		}
+	}
+
+	validation.DataOffsets = append(validation.DataOffsets, cogFirstBlockOffset(mainBand))
+	for _, overview := range overviews {
+		validation.DataOffsets = append(validation.DataOffsets, cogFirstBlockOffset(overview))
+	}
+
+	last := len(validation.DataOffsets) - 1
+	if validation.DataOffsets[last] != 0 && validation.DataOffsets[last] < validation.IFDOffsets[last] {
+		if len(overviews) > 0 {
+			validation.add(COGError, "the offset of the first block of the smallest overview should be after its IFD")
+		} else {
+			validation.add(COGError, "the offset of the first block of the image should be after its IFD")
+		}
+	}
+	for i := last - 1; i > 0; i-- {
+		if validation.DataOffsets[i] != 0 && validation.DataOffsets[i] < validation.DataOffsets[i+1] {
+			validation.add(COGError,
+				"the offset of the first block of overview of index %d should be after the one of the overview of index %d",
+				i-1, i)
+		}
+	}
+	if last >= 1 && validation.DataOffsets[0] != 0 && validation.DataOffsets[0] < validation.DataOffsets[1] {
+		validation.add(COGError,
+			"the offset of the first block of the main resolution image should be after the one of the overview of index %d",
+			len(overviews)-1)
+	}
+
+	return validation, nil
+}
+
+// cogOffset returns the integer TIFF metadata item name of rasterBand.
+func cogOffset(rasterBand RasterBand, name string) (int64, error) {
+	value := rasterBand.MetadataItem(name, "TIFF")
+	offset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error: missing or invalid TIFF %s metadata %q", name, value)
+	}
+	return offset, nil
+}
+
+// cogFirstBlockOffset returns the offset of the first written block of
+// rasterBand, or 0 if no block is written.
+func cogFirstBlockOffset(rasterBand RasterBand) int64 {
+	blockXSize, blockYSize := rasterBand.BlockSize()
+	if blockXSize <= 0 || blockYSize <= 0 {
+		return 0
+	}
+	xBlocks := (rasterBand.XSize() + blockXSize - 1) / blockXSize
+	yBlocks := (rasterBand.YSize() + blockYSize - 1) / blockYSize
+	for y := 0; y < yBlocks; y++ {
+		for x := 0; x < xBlocks; x++ {
+			value := rasterBand.MetadataItem(fmt.Sprintf("BLOCK_OFFSET_%d_%d", x, y), "TIFF")
+			if offset, err := strconv.ParseInt(value, 10, 64); err == nil && offset != 0 {
+				return offset
+			}
+		}
+	}
+	return 0
+}
+
+// cogExpectedIFDOffset returns the offset at which the main IFD of the TIFF
+// file at path is expected: right after the header and the optional GDAL
+// structural metadata, which is returned as well.
+func cogExpectedIFDOffset(path string) (int64, map[string]string) {
+	file, err := VSIFOpenL(path, "rb")
+	if err != nil {
+		return 8, nil
+	}
+	header := VSIFReadL(1, 1024, file)
+	VSIFCloseL(file)
+
+	expected := int64(8)
+	if len(header) >= 4 && (header[2] == 43 || header[3] == 43) {
+		expected = 16
+	}
+
+	pos := bytes.Index(header, []byte(cogStructuralMetadataKey))
+	if pos < 0 {
+		return expected, nil
+	}
+	sizeStart := pos + len(cogStructuralMetadataKey)
+	if sizeStart+6 > len(header) {
+		return expected, nil
+	}
+	size, err := strconv.Atoi(string(header[sizeStart : sizeStart+6]))
+	if err != nil {
+		return expected, nil
+	}
+	content := sizeStart + len("000000 bytes\n")
+	expected = int64(content + size)
+	// IFDs start on a word boundary.
+	expected += expected % 2
+
+	metadata := map[string]string{}
+	end := content + size
+	if end > len(header) {
+		end = len(header)
+	}
+	for _, line := range strings.Split(string(header[content:end]), "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(parts) == 2 && parts[0] != "" {
+			metadata[parts[0]] = parts[1]
+		}
+	}
+	return expected, metadata
+}
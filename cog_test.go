@@ -0,0 +1,101 @@
+package gdal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCOGOptionsCreationOptions(t *testing.T) {
+	opts := COGOptions{
+		Compression:        COGCompressionDeflate,
+		Level:              6,
+		BlockSize:          256,
+		Predictor:          COGPredictorStandard,
+		OverviewResampling: "AVERAGE",
+		TargetSRS:          "EPSG:3857",
+		TilingScheme:       COGTilingSchemeGoogleMapsCompatible,
+		CreationOptions:    []string{"STATISTICS=YES"},
+	}
+	got, err := opts.creationOptions()
+	if err != nil {
+		t.Fatalf("creationOptions: %v", err)
+	}
+	want := []string{
+		"COMPRESS=DEFLATE",
+		"LEVEL=6",
+		"BLOCKSIZE=256",
+		"PREDICTOR=STANDARD",
+		"OVERVIEW_RESAMPLING=AVERAGE",
+		"TARGET_SRS=EPSG:3857",
+		"TILING_SCHEME=GoogleMapsCompatible",
+		"STATISTICS=YES",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("creationOptions() = %v, want %v", got, want)
+	}
+
+	for _, invalid := range []COGOptions{{BlockSize: 100}, {Quality: 101}, {Level: -1}} {
+		if _, err := invalid.creationOptions(); err == nil {
+			t.Errorf("creationOptions(%+v): expected error", invalid)
+		}
+	}
+}
+
+func TestWriteAndValidateCOG(t *testing.T) {
+	src := createGTiffRasterDataset(t, "./tmp/cog_source.tif", 1024, 1024, 1)
+	defer src.Close()
+
+	filename := "./tmp/cog.tif"
+	err := WriteCOG(src, filename, COGOptions{
+		Compression:        COGCompressionDeflate,
+		BlockSize:          256,
+		OverviewResampling: "AVERAGE",
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("WriteCOG: %v", err)
+	}
+
+	validation, err := ValidateCOG(filename)
+	if err != nil {
+		t.Fatalf("ValidateCOG: %v", err)
+	}
+	if !validation.Valid() {
+		t.Errorf("ValidateCOG errors: %v", validation.Errors())
+	}
+	if len(validation.IFDOffsets) < 2 {
+		t.Errorf("IFDOffsets = %v, want main image and overviews", validation.IFDOffsets)
+	}
+	if got := validation.StructuralMetadata["LAYOUT"]; got != "COG" {
+		t.Errorf("LAYOUT = %q, want COG", got)
+	}
+
+	ds, err := Open(filename, ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer ds.Close()
+	if x, y := ds.RasterBand(1).BlockSize(); x != 256 || y != 256 {
+		t.Errorf("BlockSize() = %dx%d, want 256x256", x, y)
+	}
+}
+
+func TestValidateCOGStrippedGeoTIFF(t *testing.T) {
+	filename := "./tmp/cog_stripped.tif"
+	ds := createGTiffRasterDataset(t, filename, 2048, 600, 1)
+	ds.Close()
+
+	validation, err := ValidateCOG(filename)
+	if err != nil {
+		t.Fatalf("ValidateCOG: %v", err)
+	}
+	if validation.Valid() {
+		t.Error("stripped GeoTIFF reported as valid COG")
+	}
+	if len(validation.Warnings()) == 0 {
+		t.Error("expected a warning about missing overviews")
+	}
+
+	if _, err := ValidateCOG("testdata/demproc_colors.txt"); err == nil {
+		t.Error("expected error for a non-GeoTIFF file")
+	}
+}
@@ -0,0 +1,467 @@
+package gdal
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ColorStop maps a value to a color.
+type ColorStop struct {
+	// Value is the band value, or a percentage of the band range when
+	// Percent is set.
+	Value   float64
+	Percent bool
+	Color   color.NRGBA
+	Label   string
+}
+
+// ColorMap is an ordered list of color stops with an optional nodata color.
+// It converts between GDAL color relief files, QGIS color map exports and
+// styles, color palettes and color tables.
+type ColorMap struct {
+	Stops  []ColorStop
+	NoData *color.NRGBA
+}
+
+// namedColors are the color names understood by gdaldem color-relief.
+var namedColors = map[string]color.NRGBA{
+	"white":   {255, 255, 255, 255},
+	"black":   {0, 0, 0, 255},
+	"red":     {255, 0, 0, 255},
+	"green":   {0, 255, 0, 255},
+	"blue":    {0, 0, 255, 255},
+	"yellow":  {255, 255, 0, 255},
+	"magenta": {255, 0, 255, 255},
+	"cyan":    {0, 255, 255, 255},
+	"aqua":    {0, 192, 192, 255},
+	"grey":    {192, 192, 192, 255},
+	"gray":    {192, 192, 192, 255},
+	"orange":  {255, 128, 0, 255},
+	"brown":   {128, 64, 0, 255},
+	"purple":  {128, 0, 128, 255},
+	"violet":  {255, 128, 255, 255},
+	"indigo":  {0, 64, 128, 255},
+}
+
+// ReadColorRelief parses a gdaldem color-relief text file. Each line holds a
+// value, a percentage such as 20% or nv for nodata, followed by either
+// r g b [a] components or a color name.
+func ReadColorRelief(r io.Reader) (ColorMap, error) {
+	var colorMap ColorMap
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ',' || r == ':'
+		})
+		if len(fields) < 2 {
+			return ColorMap{}, fmt.Errorf("color relief line %d: %q is incomplete", line, text)
+		}
+
+		c, err := parseColorComponents(fields[1:])
+		if err != nil {
+			return ColorMap{}, fmt.Errorf("color relief line %d: %v", line, err)
+		}
+
+		if value := strings.ToLower(fields[0]); value == "nv" {
+			colorMap.NoData = &c
+			continue
+		}
+		stop := ColorStop{Color: c}
+		value := fields[0]
+		if strings.HasSuffix(value, "%") {
+			stop.Percent = true
+			value = strings.TrimSuffix(value, "%")
+		}
+		if stop.Value, err = strconv.ParseFloat(value, 64); err != nil {
+			return ColorMap{}, fmt.Errorf("color relief line %d: invalid value %q", line, fields[0])
+		}
+		colorMap.Stops = append(colorMap.Stops, stop)
+	}
+	if err := scanner.Err(); err != nil {
+		return ColorMap{}, err
+	}
+	return colorMap, nil
+}
+
+func parseColorComponents(fields []string) (color.NRGBA, error) {
+	if len(fields) == 1 {
+		c, ok := namedColors[strings.ToLower(fields[0])]
+		if !ok {
+			return color.NRGBA{}, fmt.Errorf("unknown color %q", fields[0])
+		}
+		return c, nil
+	}
+	if len(fields) < 3 || len(fields) > 4 {
+		return color.NRGBA{}, fmt.Errorf("expected 3 or 4 color components, got %d", len(fields))
+	}
+	components := [4]uint8{0, 0, 0, 255}
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 || value > 255 {
+			return color.NRGBA{}, fmt.Errorf("invalid color component %q", field)
+		}
+		components[i] = uint8(value)
+	}
+	return color.NRGBA{components[0], components[1], components[2], components[3]}, nil
+}
+
+// WriteColorRelief writes the color map as a gdaldem color-relief text file.
+func (colorMap ColorMap) WriteColorRelief(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, stop := range colorMap.Stops {
+		value := formatFloat(stop.Value)
+		if stop.Percent {
+			value += "%"
+		}
+		c := stop.Color
+		fmt.Fprintf(bw, "%s %d %d %d %d\n", value, c.R, c.G, c.B, c.A)
+	}
+	if c := colorMap.NoData; c != nil {
+		fmt.Fprintf(bw, "nv %d %d %d %d\n", c.R, c.G, c.B, c.A)
+	}
+	return bw.Flush()
+}
+
+// ReadQGISColorMap parses a QGIS color map export (.clr or .txt) with
+// value,r,g,b,a,label lines. Whitespace separated value r g b [a] lines are
+// accepted as well.
+func ReadQGISColorMap(r io.Reader) (ColorMap, error) {
+	var colorMap ColorMap
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(strings.ToUpper(text), "INTERPOLATION:") {
+			continue
+		}
+
+		var fields []string
+		label := ""
+		if strings.Contains(text, ",") {
+			fields = strings.SplitN(text, ",", 6)
+			if len(fields) == 6 {
+				label = fields[5]
+				fields = fields[:5]
+			}
+		} else {
+			fields = strings.Fields(text)
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if len(fields) < 4 {
+			return ColorMap{}, fmt.Errorf("color map line %d: %q is incomplete", line, text)
+		}
+
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return ColorMap{}, fmt.Errorf("color map line %d: invalid value %q", line, fields[0])
+		}
+		c, err := parseColorComponents(fields[1:])
+		if err != nil {
+			return ColorMap{}, fmt.Errorf("color map line %d: %v", line, err)
+		}
+		colorMap.Stops = append(colorMap.Stops, ColorStop{Value: value, Color: c, Label: label})
+	}
+	if err := scanner.Err(); err != nil {
+		return ColorMap{}, err
+	}
+	return colorMap, nil
+}
+
+// WriteQGISColorMap writes the color map in the QGIS color map export format.
+// Percent stops are not supported by QGIS and are rejected.
+func (colorMap ColorMap) WriteQGISColorMap(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# QGIS Generated Color Map Export File\nINTERPOLATION:INTERPOLATED\n")
+	for _, stop := range colorMap.Stops {
+		if stop.Percent {
+			return fmt.Errorf("percent color stops cannot be exported to QGIS")
+		}
+		label := stop.Label
+		if label == "" {
+			label = formatFloat(stop.Value)
+		}
+		c := stop.Color
+		fmt.Fprintf(bw, "%s,%d,%d,%d,%d,%s\n", formatFloat(stop.Value), c.R, c.G, c.B, c.A, label)
+	}
+	return bw.Flush()
+}
+
+type qmlItem struct {
+	Value string `xml:"value,attr"`
+	Color string `xml:"color,attr"`
+	Alpha string `xml:"alpha,attr,omitempty"`
+	Label string `xml:"label,attr"`
+}
+
+// ReadQML parses the color ramp shader items or palette entries of a QGIS
+// raster style file.
+func ReadQML(r io.Reader) (ColorMap, error) {
+	var colorMap ColorMap
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ColorMap{}, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || (start.Name.Local != "item" && start.Name.Local != "paletteEntry") {
+			continue
+		}
+
+		var item qmlItem
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return ColorMap{}, err
+		}
+		value, err := strconv.ParseFloat(item.Value, 64)
+		if err != nil {
+			return ColorMap{}, fmt.Errorf("qml item value %q is invalid", item.Value)
+		}
+		c, err := parseHexColor(item.Color)
+		if err != nil {
+			return ColorMap{}, err
+		}
+		if item.Alpha != "" {
+			alpha, err := strconv.Atoi(item.Alpha)
+			if err != nil || alpha < 0 || alpha > 255 {
+				return ColorMap{}, fmt.Errorf("qml item alpha %q is invalid", item.Alpha)
+			}
+			c.A = uint8(alpha)
+		}
+		colorMap.Stops = append(colorMap.Stops, ColorStop{Value: value, Color: c, Label: item.Label})
+	}
+	return colorMap, nil
+}
+
+// WriteQML writes the color map as a QGIS single band pseudocolor style for
+// band 1. Percent stops are not supported by QGIS and are rejected.
+func (colorMap ColorMap) WriteQML(w io.Writer) error {
+	type colorRampShader struct {
+		Type  string    `xml:"colorRampType,attr"`
+		Items []qmlItem `xml:"item"`
+	}
+	type qml struct {
+		XMLName  xml.Name `xml:"qgis"`
+		Renderer struct {
+			Type    string `xml:"type,attr"`
+			Band    int    `xml:"band,attr"`
+			Opacity int    `xml:"opacity,attr"`
+			Shader  struct {
+				ColorRampShader colorRampShader `xml:"colorrampshader"`
+			} `xml:"rastershader"`
+		} `xml:"pipe>rasterrenderer"`
+	}
+
+	var doc qml
+	doc.Renderer.Type = "singlebandpseudocolor"
+	doc.Renderer.Band = 1
+	doc.Renderer.Opacity = 1
+	doc.Renderer.Shader.ColorRampShader.Type = "INTERPOLATED"
+	for _, stop := range colorMap.Stops {
+		if stop.Percent {
+			return fmt.Errorf("percent color stops cannot be exported to QGIS")
+		}
+		label := stop.Label
+		if label == "" {
+			label = formatFloat(stop.Value)
+		}
+		c := stop.Color
+		doc.Renderer.Shader.ColorRampShader.Items = append(doc.Renderer.Shader.ColorRampShader.Items, qmlItem{
+			Value: formatFloat(stop.Value),
+			Color: fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B),
+			Alpha: strconv.Itoa(int(c.A)),
+			Label: label,
+		})
+	}
+
+	if _, err := io.WriteString(w, "<!DOCTYPE qgis PUBLIC 'http://mrcc.com/qgis.dtd' 'SYSTEM'>\n"); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func parseHexColor(text string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(text, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("color %q is invalid", text)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("color %q is invalid", text)
+	}
+	if len(hex) == 6 {
+		return color.NRGBA{uint8(value >> 16), uint8(value >> 8), uint8(value), 255}, nil
+	}
+	return color.NRGBA{uint8(value >> 24), uint8(value >> 16), uint8(value >> 8), uint8(value)}, nil
+}
+
+// ColorMapFromPalette returns a color map with one stop per palette entry,
+// using the entry index as value.
+func ColorMapFromPalette(palette color.Palette) ColorMap {
+	colorMap := ColorMap{Stops: make([]ColorStop, len(palette))}
+	for i, c := range palette {
+		colorMap.Stops[i] = ColorStop{Value: float64(i), Color: color.NRGBAModel.Convert(c).(color.NRGBA)}
+	}
+	return colorMap
+}
+
+// Palette returns the colors of a table with size entries, as used by
+// ColorTable. Percent stops are resolved against [0, size-1].
+func (colorMap ColorMap) Palette(size int) color.Palette {
+	return colorMap.palette(size, 0, float64(size-1))
+}
+
+// palette returns the colors of a table with size entries, with percent stops
+// resolved against [min, max].
+func (colorMap ColorMap) palette(size int, min, max float64) color.Palette {
+	stops := colorMap.resolve(min, max)
+	palette := make(color.Palette, size)
+	for i := range palette {
+		palette[i] = colorAt(stops, float64(i))
+	}
+	return palette
+}
+
+// At returns the color of value, interpolated linearly between stops and
+// clamped to the first and last stop. Percent stops are resolved against
+// [min, max].
+func (colorMap ColorMap) At(value, min, max float64) color.NRGBA {
+	return colorAt(colorMap.resolve(min, max), value)
+}
+
+// colorAt interpolates value between stops sorted by value.
+func colorAt(stops []ColorStop, value float64) color.NRGBA {
+	if len(stops) == 0 {
+		return color.NRGBA{}
+	}
+	if value <= stops[0].Value {
+		return stops[0].Color
+	}
+	for i := 1; i < len(stops); i++ {
+		if value > stops[i].Value {
+			continue
+		}
+		low, high := stops[i-1], stops[i]
+		if high.Value == low.Value {
+			return high.Color
+		}
+		t := (value - low.Value) / (high.Value - low.Value)
+		lerp := func(a, b uint8) uint8 {
+			return uint8(math.Round(float64(a) + t*(float64(b)-float64(a))))
+		}
+		return color.NRGBA{
+			lerp(low.Color.R, high.Color.R),
+			lerp(low.Color.G, high.Color.G),
+			lerp(low.Color.B, high.Color.B),
+			lerp(low.Color.A, high.Color.A),
+		}
+	}
+	return stops[len(stops)-1].Color
+}
+
+// resolve returns the stops with percentages converted to values, sorted.
+func (colorMap ColorMap) resolve(min, max float64) []ColorStop {
+	stops := make([]ColorStop, len(colorMap.Stops))
+	for i, stop := range colorMap.Stops {
+		if stop.Percent {
+			stop.Value = min + (max-min)*stop.Value/100
+			stop.Percent = false
+		}
+		stops[i] = stop
+	}
+	sort.SliceStable(stops, func(i, j int) bool { return stops[i].Value < stops[j].Value })
+	return stops
+}
+
+// ColorTable returns an RGB color table with size entries filled from the
+// color map, with percent stops resolved as by Palette. The caller is
+// responsible for destroying it.
+func (colorMap ColorMap) ColorTable(size int) ColorTable {
+	return colorMap.colorTable(colorMap.Palette(size))
+}
+
+func (colorMap ColorMap) colorTable(palette color.Palette) ColorTable {
+	ct := CreateColorTable(PI_RGB)
+	for i, c := range palette {
+		nrgba := c.(color.NRGBA)
+		var entry ColorEntry
+		entry.Set(uint(nrgba.R), uint(nrgba.G), uint(nrgba.B), uint(nrgba.A))
+		ct.SetEntry(i, entry)
+	}
+	return ct
+}
+
+// ColorMapFromColorTable returns a color map with one stop per entry of an
+// RGB color table.
+func ColorMapFromColorTable(ct ColorTable) ColorMap {
+	colorMap := ColorMap{Stops: make([]ColorStop, ct.EntryCount())}
+	for i := range colorMap.Stops {
+		entry := ct.Entry(i)
+		r, g, b, a := entry.Get()
+		colorMap.Stops[i] = ColorStop{Value: float64(i), Color: color.NRGBA{r, g, b, a}}
+	}
+	return colorMap
+}
+
+// ApplyColorMap sets a color table built from colorMap on a Byte or UInt16
+// band and sets its color interpretation to palette index. Percent stops are
+// resolved against the band minimum and maximum, taken from its statistics.
+// The nodata value of the band, if any, gets the nodata color of the map.
+func (rasterBand RasterBand) ApplyColorMap(colorMap ColorMap) error {
+	var size int
+	switch rasterBand.RasterDataType() {
+	case Byte:
+		size = 256
+	case UInt16:
+		size = 65536
+	default:
+		return fmt.Errorf("color tables require a Byte or UInt16 band, got %s", rasterBand.RasterDataType().Name())
+	}
+
+	min, max := 0.0, float64(size-1)
+	for _, stop := range colorMap.Stops {
+		if stop.Percent {
+			stats, err := rasterBand.GetStatistics(1, 1)
+			if err != nil {
+				return err
+			}
+			min, max = stats.Min, stats.Max
+			break
+		}
+	}
+
+	ct := colorMap.colorTable(colorMap.palette(size, min, max))
+	defer ct.Destroy()
+
+	if noData, ok := rasterBand.NoDataValue(); ok && colorMap.NoData != nil && noData >= 0 && noData < float64(size) {
+		var entry ColorEntry
+		c := colorMap.NoData
+		entry.Set(uint(c.R), uint(c.G), uint(c.B), uint(c.A))
+		ct.SetEntry(int(noData), entry)
+	}
+
+	if err := rasterBand.SetColorTable(ct); err != nil {
+		return err
+	}
+	return rasterBand.SetColorInterp(CI_PaletteIndex)
+}
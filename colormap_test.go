@@ -0,0 +1,143 @@
+package gdal
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadColorRelief(t *testing.T) {
+	f, err := os.Open("testdata/demproc_colors.txt")
+	if err != nil {
+		t.Fatalf("os.Open: %v", err)
+	}
+	defer f.Close()
+
+	colorMap, err := ReadColorRelief(f)
+	if err != nil {
+		t.Fatalf("ReadColorRelief: %v", err)
+	}
+	if len(colorMap.Stops) != 6 {
+		t.Fatalf("got %d stops, want 6", len(colorMap.Stops))
+	}
+	if stop := colorMap.Stops[1]; !stop.Percent || stop.Value != 20 || stop.Color != (color.NRGBA{0, 0, 255, 255}) {
+		t.Errorf("Stops[1] = %+v, want 20%% blue", stop)
+	}
+	if colorMap.NoData == nil || *colorMap.NoData != (color.NRGBA{}) {
+		t.Errorf("NoData = %v, want transparent black", colorMap.NoData)
+	}
+	if got := colorMap.At(50, 0, 100); got != (color.NRGBA{128, 255, 0, 255}) {
+		t.Errorf("At(50) = %v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := colorMap.WriteColorRelief(&buf); err != nil {
+		t.Fatalf("WriteColorRelief: %v", err)
+	}
+	again, err := ReadColorRelief(&buf)
+	if err != nil {
+		t.Fatalf("ReadColorRelief(written): %v", err)
+	}
+	if !reflect.DeepEqual(again, colorMap) {
+		t.Errorf("round trip = %+v, want %+v", again, colorMap)
+	}
+
+	for _, text := range []string{"10 1 2", "10 chartreuse", "x 1 2 3", "10 1 2 300"} {
+		if _, err := ReadColorRelief(strings.NewReader(text)); err == nil {
+			t.Errorf("ReadColorRelief(%q): expected error", text)
+		}
+	}
+}
+
+func TestQGISColorMapRoundTrip(t *testing.T) {
+	colorMap := ColorMap{Stops: []ColorStop{
+		{Value: 0, Color: color.NRGBA{215, 25, 28, 255}, Label: "low"},
+		{Value: 100, Color: color.NRGBA{43, 131, 186, 128}, Label: "high"},
+	}}
+
+	var clr bytes.Buffer
+	if err := colorMap.WriteQGISColorMap(&clr); err != nil {
+		t.Fatalf("WriteQGISColorMap: %v", err)
+	}
+	fromCLR, err := ReadQGISColorMap(&clr)
+	if err != nil {
+		t.Fatalf("ReadQGISColorMap: %v", err)
+	}
+	if !reflect.DeepEqual(fromCLR, colorMap) {
+		t.Errorf("clr round trip = %+v, want %+v", fromCLR, colorMap)
+	}
+
+	var qml bytes.Buffer
+	if err := colorMap.WriteQML(&qml); err != nil {
+		t.Fatalf("WriteQML: %v", err)
+	}
+	fromQML, err := ReadQML(&qml)
+	if err != nil {
+		t.Fatalf("ReadQML: %v", err)
+	}
+	if !reflect.DeepEqual(fromQML, colorMap) {
+		t.Errorf("qml round trip = %+v, want %+v", fromQML, colorMap)
+	}
+
+	percent := ColorMap{Stops: []ColorStop{{Value: 10, Percent: true}}}
+	if err := percent.WriteQML(&qml); err == nil {
+		t.Error("expected error for percent stops")
+	}
+}
+
+func TestColorMapPaletteAndBand(t *testing.T) {
+	palette := color.Palette{color.NRGBA{0, 0, 0, 255}, color.NRGBA{255, 255, 255, 255}}
+	colorMap := ColorMapFromPalette(palette)
+	if got := colorMap.Palette(2); !reflect.DeepEqual(got, palette) {
+		t.Errorf("Palette(2) = %v, want %v", got, palette)
+	}
+
+	ds := createMemoryRasterDataset(t, 2, 2, 1, Byte)
+	defer ds.Close()
+
+	band := ds.RasterBand(1)
+	if err := band.ApplyColorMap(colorMap); err != nil {
+		t.Fatalf("ApplyColorMap: %v", err)
+	}
+	if got := band.ColorInterp(); got != CI_PaletteIndex {
+		t.Errorf("ColorInterp() = %v, want CI_PaletteIndex", got)
+	}
+
+	fromTable := ColorMapFromColorTable(band.ColorTable())
+	if len(fromTable.Stops) != 256 {
+		t.Fatalf("color table has %d entries, want 256", len(fromTable.Stops))
+	}
+	if got := fromTable.Stops[200].Color; got != (color.NRGBA{255, 255, 255, 255}) {
+		t.Errorf("entry 200 = %v, want white", got)
+	}
+
+	if err := band.IO(Write, 0, 0, 2, 2, []uint8{10, 10, 20, 20}, 2, 2, 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Write): %v", err)
+	}
+	percent := ColorMap{Stops: []ColorStop{
+		{Value: 0, Percent: true, Color: color.NRGBA{0, 0, 0, 255}},
+		{Value: 100, Percent: true, Color: color.NRGBA{255, 255, 255, 255}},
+	}}
+	if err := band.ApplyColorMap(percent); err != nil {
+		t.Fatalf("ApplyColorMap(percent): %v", err)
+	}
+	fromTable = ColorMapFromColorTable(band.ColorTable())
+	for entry, want := range map[int]color.NRGBA{
+		10: {0, 0, 0, 255},
+		15: {128, 128, 128, 255},
+		20: {255, 255, 255, 255},
+	} {
+		if got := fromTable.Stops[entry].Color; got != want {
+			t.Errorf("percent entry %d = %v, want %v", entry, got, want)
+		}
+	}
+
+	floatDS := createMemoryRasterDataset(t, 1, 1, 1, Float32)
+	defer floatDS.Close()
+	if err := floatDS.RasterBand(1).ApplyColorMap(colorMap); err == nil {
+		t.Error("expected error for Float32 band")
+	}
+}
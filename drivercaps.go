@@ -0,0 +1,132 @@
+package gdal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DriverCapabilities summarizes the capabilities advertised by a driver.
+type DriverCapabilities struct {
+	Raster         bool
+	Vector         bool
+	MultidimRaster bool
+	Open           bool
+	Create         bool
+	CreateCopy     bool
+	VirtualIO      bool
+	Subdatasets    bool
+	// DataTypes lists the raster data types the driver can create.
+	DataTypes []DataType
+	// Extensions lists the file extensions, primary extension first.
+	Extensions  []string
+	MIMEType    string
+	OpenOptions []OptionDescriptor
+}
+
+// HasCapability reports whether the driver metadata item capability, such
+// as DCAP_RASTER or DCAP_CREATE, is set to YES.
+func (driver Driver) HasCapability(capability string) bool {
+	return strings.EqualFold(driver.MetadataItem(capability, ""), "YES")
+}
+
+// Extensions returns the file extensions handled by the driver, primary
+// extension first.
+func (driver Driver) Extensions() []string {
+	var extensions []string
+	seen := map[string]bool{}
+	add := func(extension string) {
+		extension = strings.ToLower(strings.TrimPrefix(extension, "."))
+		if extension != "" && !seen[extension] {
+			seen[extension] = true
+			extensions = append(extensions, extension)
+		}
+	}
+	add(driver.MetadataItem(DMD_EXTENSION, ""))
+	for _, extension := range strings.Fields(driver.MetadataItem(DMD_EXTENSIONS, "")) {
+		add(extension)
+	}
+	return extensions
+}
+
+// CreationDataTypes returns the raster data types the driver can create.
+func (driver Driver) CreationDataTypes() []DataType {
+	var dataTypes []DataType
+	for _, name := range strings.Fields(driver.MetadataItem(DMD_CREATIONDATATYPES, "")) {
+		if dataType := DataTypeByName(name); dataType != Unknown {
+			dataTypes = append(dataTypes, dataType)
+		}
+	}
+	return dataTypes
+}
+
+// Capabilities returns the typed capabilities of the driver.
+func (driver Driver) Capabilities() (DriverCapabilities, error) {
+	openOptions, err := driver.OpenOptions()
+	if err != nil {
+		return DriverCapabilities{}, err
+	}
+	return DriverCapabilities{
+		Raster:         driver.HasCapability(DCAP_RASTER),
+		Vector:         driver.HasCapability(DCAP_VECTOR),
+		MultidimRaster: driver.HasCapability(DCAP_MULTIDIM_RASTER),
+		Open:           driver.HasCapability(DCAP_OPEN),
+		Create:         driver.HasCapability(DCAP_CREATE),
+		CreateCopy:     driver.HasCapability(DCAP_CREATECOPY),
+		VirtualIO:      driver.HasCapability(DCAP_VIRTUALIO),
+		Subdatasets:    driver.HasCapability(DMD_SUBDATASETS),
+		DataTypes:      driver.CreationDataTypes(),
+		Extensions:     driver.Extensions(),
+		MIMEType:       driver.MetadataItem(DMD_MIMETYPE, ""),
+		OpenOptions:    openOptions,
+	}, nil
+}
+
+// Drivers returns the registered drivers that have all of the given
+// capabilities, e.g. Drivers(DCAP_RASTER, DCAP_CREATECOPY).
+func Drivers(capabilities ...string) []Driver {
+	var drivers []Driver
+	for i := 0; i < GetDriverCount(); i++ {
+		driver := GetDriver(i)
+		matches := true
+		for _, capability := range capabilities {
+			if !driver.HasCapability(capability) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			drivers = append(drivers, driver)
+		}
+	}
+	return drivers
+}
+
+// DriverForExtension returns a driver handling extension and having all of
+// the given capabilities. Drivers whose primary extension matches are
+// preferred over those listing it as an alternative.
+func DriverForExtension(extension string, capabilities ...string) (Driver, error) {
+	extension = strings.ToLower(strings.TrimPrefix(extension, "."))
+	if extension == "" {
+		return Driver{}, fmt.Errorf("extension must not be empty")
+	}
+
+	var fallback *Driver
+	for _, driver := range Drivers(capabilities...) {
+		for i, candidate := range driver.Extensions() {
+			if candidate != extension {
+				continue
+			}
+			if i == 0 {
+				return driver, nil
+			}
+			if fallback == nil {
+				d := driver
+				fallback = &d
+			}
+		}
+	}
+	if fallback != nil {
+		return *fallback, nil
+	}
+	return Driver{}, fmt.Errorf("no driver found for extension %q", extension)
+}
@@ -0,0 +1,82 @@
+package gdal
+
+import "testing"
+
+func TestDriverCapabilities(t *testing.T) {
+	driver, err := GetDriverByName(DriverNameGTiff)
+	if err != nil {
+		t.Fatalf("GetDriverByName(GTiff): %v", err)
+	}
+
+	capabilities, err := driver.Capabilities()
+	if err != nil {
+		t.Fatalf("Capabilities: %v", err)
+	}
+	if !capabilities.Raster || capabilities.Vector || !capabilities.Create || !capabilities.CreateCopy || !capabilities.VirtualIO {
+		t.Errorf("Capabilities() = %+v", capabilities)
+	}
+	if capabilities.MIMEType != "image/tiff" {
+		t.Errorf("MIMEType = %q, want image/tiff", capabilities.MIMEType)
+	}
+	if len(capabilities.Extensions) == 0 || capabilities.Extensions[0] != "tif" {
+		t.Errorf("Extensions = %v, want tif first", capabilities.Extensions)
+	}
+	hasFloat32 := false
+	for _, dataType := range capabilities.DataTypes {
+		hasFloat32 = hasFloat32 || dataType == Float32
+	}
+	if !hasFloat32 {
+		t.Errorf("DataTypes = %v, want Float32 included", capabilities.DataTypes)
+	}
+	if len(capabilities.OpenOptions) == 0 {
+		t.Error("OpenOptions is empty")
+	}
+
+	gpkg, err := GetDriverByName(DriverNameGPKG)
+	if err != nil {
+		t.Fatalf("GetDriverByName(GPKG): %v", err)
+	}
+	if !gpkg.HasCapability(DCAP_VECTOR) || !gpkg.HasCapability(DMD_SUBDATASETS) {
+		t.Error("GPKG should support vector and subdatasets")
+	}
+}
+
+func TestDriversFilteredByCapability(t *testing.T) {
+	vector := Drivers(DCAP_VECTOR, DCAP_CREATE)
+	if len(vector) == 0 {
+		t.Fatal("Drivers(DCAP_VECTOR, DCAP_CREATE) is empty")
+	}
+	for _, driver := range vector {
+		if !driver.HasCapability(DCAP_VECTOR) || !driver.HasCapability(DCAP_CREATE) {
+			t.Errorf("driver %s lacks a requested capability", driver.ShortName())
+		}
+	}
+	if all := Drivers(); len(all) != GetDriverCount() {
+		t.Errorf("Drivers() returned %d drivers, want %d", len(all), GetDriverCount())
+	}
+}
+
+func TestDriverForExtension(t *testing.T) {
+	tests := map[string]string{
+		".TIF": DriverNameGTiff,
+		"gpkg": DriverNameGPKG,
+		"png":  DriverNamePNG,
+	}
+	for extension, want := range tests {
+		driver, err := DriverForExtension(extension, DCAP_RASTER)
+		if err != nil {
+			t.Errorf("DriverForExtension(%q): %v", extension, err)
+			continue
+		}
+		if got := driver.ShortName(); got != want {
+			t.Errorf("DriverForExtension(%q) = %s, want %s", extension, got, want)
+		}
+	}
+
+	if _, err := DriverForExtension("no-such-ext"); err == nil {
+		t.Error("expected error for unknown extension")
+	}
+	if _, err := DriverForExtension(""); err == nil {
+		t.Error("expected error for empty extension")
+	}
+}
@@ -0,0 +1,420 @@
+package gdal
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+var (
+	geometryType = reflect.TypeOf(Geometry{})
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// ogrStructField maps an exported struct field to an OGR field name.
+type ogrStructField struct {
+	index    int
+	name     string
+	tagged   bool
+	geometry bool
+}
+
+// ogrStructFields returns the fields of structType taking part in feature
+// marshalling. Fields are named by an `ogr:"name"` tag or by the Go field
+// name; fields tagged `ogr:"-"` are skipped. A field of type Geometry holds
+// the feature geometry.
+func ogrStructFields(structType reflect.Type) ([]ogrStructField, error) {
+	fields := []ogrStructField{}
+	hasGeometry := false
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, tagged := field.Tag.Lookup("ogr")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		geometry := field.Type == geometryType
+		if geometry {
+			if hasGeometry {
+				return nil, fmt.Errorf("error: %s has more than one geometry field", structType)
+			}
+			hasGeometry = true
+		}
+		fields = append(fields, ogrStructField{index: i, name: name, tagged: tagged, geometry: geometry})
+	}
+	return fields, nil
+}
+
+// structValue returns the struct referred to by v, which must be a struct or
+// a pointer to one.
+func structValue(v interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("error: expected a struct or a pointer to a struct, got %T", v)
+	}
+	return value, nil
+}
+
+// Unmarshal copies the fields and the geometry of the feature into the
+// struct pointed to by v. Fields are matched as described for Marshal. Null
+// or unset fields leave nil pointers and zero values. The geometry is a
+// clone owned by the caller.
+func (feature Feature) Unmarshal(v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("error: destination must be a non-nil pointer to a struct, got %T", v)
+	}
+	value = value.Elem()
+
+	fields, err := ogrStructFields(value.Type())
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		target := value.Field(field.index)
+		if field.geometry {
+			geom := feature.Geometry()
+			if !geom.IsNull() {
+				geom = geom.Clone()
+			}
+			target.Set(reflect.ValueOf(geom))
+			continue
+		}
+		index := feature.FieldIndex(field.name)
+		if index < 0 {
+			if field.tagged {
+				return fmt.Errorf("error: field %q not found", field.name)
+			}
+			continue
+		}
+		if err := feature.unmarshalField(index, target); err != nil {
+			return fmt.Errorf("error: field %q: %v", field.name, err)
+		}
+	}
+	return nil
+}
+
+func (feature Feature) unmarshalField(index int, target reflect.Value) error {
+	if !feature.IsFieldSetAndNotNull(index) {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	if target.Kind() == reflect.Ptr {
+		elem := reflect.New(target.Type().Elem())
+		if err := feature.unmarshalField(index, elem.Elem()); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	}
+
+	if target.Type() == timeType {
+		t, ok := feature.FieldAsDateTime(index)
+		if !ok {
+			return fmt.Errorf("value is not a date")
+		}
+		target.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Bool:
+		target.SetBool(feature.FieldAsInteger64(index) != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return setInt(target, feature.FieldAsInteger64(index))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return setInt(target, feature.FieldAsInteger64(index))
+	case reflect.Float32, reflect.Float64:
+		target.SetFloat(feature.FieldAsFloat64(index))
+	case reflect.String:
+		target.SetString(feature.FieldAsString(index))
+	case reflect.Slice:
+		elemType := target.Type().Elem()
+		switch elemType.Kind() {
+		case reflect.Uint8:
+			target.SetBytes(feature.FieldAsBinary(index))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			var values []int64
+			if feature.FieldDefinition(index).Type() == FT_IntegerList {
+				for _, value := range feature.FieldAsIntegerList(index) {
+					values = append(values, int64(value))
+				}
+			} else {
+				values = feature.FieldAsInteger64List(index)
+			}
+			slice := reflect.MakeSlice(target.Type(), len(values), len(values))
+			for i, value := range values {
+				if err := setInt(slice.Index(i), value); err != nil {
+					return err
+				}
+			}
+			target.Set(slice)
+		case reflect.Float32, reflect.Float64:
+			values := feature.FieldAsFloat64List(index)
+			slice := reflect.MakeSlice(target.Type(), len(values), len(values))
+			for i, value := range values {
+				slice.Index(i).SetFloat(value)
+			}
+			target.Set(slice)
+		case reflect.String:
+			values := feature.FieldAsStringList(index)
+			slice := reflect.MakeSlice(target.Type(), len(values), len(values))
+			for i, value := range values {
+				slice.Index(i).SetString(value)
+			}
+			target.Set(slice)
+		default:
+			return fmt.Errorf("unsupported type %s", target.Type())
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", target.Type())
+	}
+	return nil
+}
+
+// setInt stores value into an integer of any size, failing on overflow.
+func setInt(target reflect.Value, value int64) error {
+	switch target.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value < 0 || target.OverflowUint(uint64(value)) {
+			return fmt.Errorf("value %d overflows %s", value, target.Type())
+		}
+		target.SetUint(uint64(value))
+	default:
+		if target.OverflowInt(value) {
+			return fmt.Errorf("value %d overflows %s", value, target.Type())
+		}
+		target.SetInt(value)
+	}
+	return nil
+}
+
+// Marshal sets the fields and the geometry of the feature from v, a struct
+// or a pointer to one. Struct fields are matched to OGR fields by an
+// `ogr:"name"` tag or by the Go field name; fields tagged `ogr:"-"` are
+// skipped. A tagged field without a matching OGR field is an error. A nil
+// pointer or slice sets the OGR field to null, and a field of type Geometry
+// sets a copy of the geometry, or clears it when the geometry is null.
+func (feature Feature) Marshal(v interface{}) error {
+	value, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	fields, err := ogrStructFields(value.Type())
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		source := value.Field(field.index)
+		if field.geometry {
+			geom := source.Interface().(Geometry)
+			if geom.IsNull() {
+				// Clear the geometry of a reused feature.
+				err = feature.SetGeometryDirectly(Geometry{})
+			} else {
+				err = feature.SetGeometry(geom)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		index := feature.FieldIndex(field.name)
+		if index < 0 {
+			if field.tagged {
+				return fmt.Errorf("error: field %q not found", field.name)
+			}
+			continue
+		}
+		if err := feature.marshalField(index, source); err != nil {
+			return fmt.Errorf("error: field %q: %v", field.name, err)
+		}
+	}
+	return nil
+}
+
+func (feature Feature) marshalField(index int, source reflect.Value) error {
+	if source.Kind() == reflect.Ptr || source.Kind() == reflect.Slice {
+		if source.IsNil() {
+			feature.SetFieldNull(index)
+			return nil
+		}
+		if source.Kind() == reflect.Ptr {
+			return feature.marshalField(index, source.Elem())
+		}
+	}
+
+	if source.Type() == timeType {
+		feature.SetFieldDateTime(index, source.Interface().(time.Time))
+		return nil
+	}
+
+	switch source.Kind() {
+	case reflect.Bool:
+		value := 0
+		if source.Bool() {
+			value = 1
+		}
+		feature.SetFieldInteger(index, value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		feature.SetFieldInteger64(index, source.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if source.Uint() > math.MaxInt64 {
+			return fmt.Errorf("value %d overflows int64", source.Uint())
+		}
+		feature.SetFieldInteger64(index, int64(source.Uint()))
+	case reflect.Float32, reflect.Float64:
+		feature.SetFieldFloat64(index, source.Float())
+	case reflect.String:
+		feature.SetFieldString(index, source.String())
+	case reflect.Slice:
+		switch source.Type().Elem().Kind() {
+		case reflect.Uint8:
+			feature.SetFieldBinary(index, source.Bytes())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			values := make([]int64, source.Len())
+			for i := range values {
+				elem := source.Index(i)
+				if elem.CanInt() {
+					values[i] = elem.Int()
+					continue
+				}
+				if elem.Uint() > math.MaxInt64 {
+					return fmt.Errorf("value %d overflows int64", elem.Uint())
+				}
+				values[i] = int64(elem.Uint())
+			}
+			if feature.FieldDefinition(index).Type() == FT_IntegerList {
+				ints := make([]int, len(values))
+				for i, value := range values {
+					if value < math.MinInt32 || value > math.MaxInt32 {
+						return fmt.Errorf("value %d overflows a 32-bit integer list", value)
+					}
+					ints[i] = int(value)
+				}
+				feature.SetFieldIntegerList(index, ints)
+			} else {
+				feature.SetFieldInteger64List(index, values)
+			}
+		case reflect.Float32, reflect.Float64:
+			values := make([]float64, source.Len())
+			for i := range values {
+				values[i] = source.Index(i).Float()
+			}
+			feature.SetFieldFloat64List(index, values)
+		case reflect.String:
+			values := make([]string, source.Len())
+			for i := range values {
+				values[i] = source.Index(i).String()
+			}
+			feature.SetFieldStringList(index, values)
+		default:
+			return fmt.Errorf("unsupported type %s", source.Type())
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", source.Type())
+	}
+	return nil
+}
+
+// fieldTypeOf returns the OGR field type and subtype used to store values
+// of goType.
+func fieldTypeOf(goType reflect.Type) (FieldType, FieldSubType, error) {
+	if goType.Kind() == reflect.Ptr {
+		goType = goType.Elem()
+	}
+	if goType == timeType {
+		return FT_DateTime, FST_None, nil
+	}
+	switch goType.Kind() {
+	case reflect.Bool:
+		return FT_Integer, FST_Boolean, nil
+	case reflect.Int16:
+		return FT_Integer, FST_Int16, nil
+	case reflect.Int8, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return FT_Integer, FST_None, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return FT_Integer64, FST_None, nil
+	case reflect.Float32:
+		return FT_Real, FST_Float32, nil
+	case reflect.Float64:
+		return FT_Real, FST_None, nil
+	case reflect.String:
+		return FT_String, FST_None, nil
+	case reflect.Slice:
+		switch goType.Elem().Kind() {
+		case reflect.Uint8:
+			return FT_Binary, FST_None, nil
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint16:
+			return FT_IntegerList, FST_None, nil
+		case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+			return FT_Integer64List, FST_None, nil
+		case reflect.Float32, reflect.Float64:
+			return FT_RealList, FST_None, nil
+		case reflect.String:
+			return FT_StringList, FST_None, nil
+		}
+	}
+	return 0, FST_None, fmt.Errorf("error: unsupported field type %s", goType)
+}
+
+// CreateSchemaFromStruct creates a layer field for every field of v, a
+// struct or a pointer to one, that the layer does not have yet. Field names
+// follow the rules of Feature.Marshal; the geometry field is ignored since
+// the layer geometry type is fixed when the layer is created. Booleans,
+// int16 and float32 values get the matching field subtype.
+func (layer Layer) CreateSchemaFromStruct(v interface{}) error {
+	value, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	fields, err := ogrStructFields(value.Type())
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if field.geometry || layer.Definition().FieldIndex(field.name) >= 0 {
+			continue
+		}
+		fieldType, subType, err := fieldTypeOf(value.Type().Field(field.index).Type)
+		if err != nil {
+			return fmt.Errorf("%v for field %q", err, field.name)
+		}
+		fd := CreateFieldDefinition(field.name, fieldType)
+		fd.SetSubType(subType)
+		err = layer.CreateField(fd, false)
+		fd.Destroy()
+		if err != nil {
+			return fmt.Errorf("error: creating field %q: %v", field.name, err)
+		}
+	}
+	return nil
+}
+
+// ReadAll reads every feature of the layer matching its current filters
+// into a slice of T using Feature.Unmarshal.
+func ReadAll[T any](layer Layer) ([]T, error) {
+	values := []T{}
+	for feature, err := range layer.Features() {
+		if err != nil {
+			return nil, err
+		}
+		var value T
+		if err := feature.Unmarshal(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,195 @@
+package gdal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type marshalRecord struct {
+	ID       int64     `ogr:"id"`
+	Count    int32     `ogr:"count"`
+	Ratio    float64   `ogr:"ratio"`
+	Name     string    `ogr:"name"`
+	Tags     []string  `ogr:"tags"`
+	Sizes    []int32   `ogr:"sizes"`
+	Weights  []float64 `ogr:"weights"`
+	Payload  []byte    `ogr:"payload"`
+	Updated  time.Time `ogr:"updated"`
+	Comment  *string   `ogr:"comment"`
+	Location Geometry
+	Ignored  string `ogr:"-"`
+}
+
+func TestFeatureMarshalRoundTrip(t *testing.T) {
+	ds, layer := createMemoryVectorLayer(t, "marshal")
+	defer ds.Destroy()
+
+	if err := layer.CreateSchemaFromStruct(marshalRecord{}); err != nil {
+		t.Fatalf("CreateSchemaFromStruct: %v", err)
+	}
+	definition := layer.Definition()
+	for name, want := range map[string]FieldType{
+		"id":      FT_Integer64,
+		"count":   FT_Integer,
+		"ratio":   FT_Real,
+		"name":    FT_String,
+		"tags":    FT_StringList,
+		"sizes":   FT_IntegerList,
+		"weights": FT_RealList,
+		"payload": FT_Binary,
+		"updated": FT_DateTime,
+		"comment": FT_String,
+	} {
+		index := definition.FieldIndex(name)
+		if index < 0 {
+			t.Fatalf("field %q not created", name)
+		}
+		if got := definition.FieldDefinition(index).Type(); got != want {
+			t.Errorf("field %q has type %v, want %v", name, got, want)
+		}
+	}
+	if definition.FieldIndex("Ignored") >= 0 || definition.FieldIndex("Location") >= 0 {
+		t.Error("ignored or geometry field was created")
+	}
+	if err := layer.CreateSchemaFromStruct(&marshalRecord{}); err != nil {
+		t.Fatalf("CreateSchemaFromStruct on existing fields: %v", err)
+	}
+
+	point, err := CreateFromWKT("POINT (1 2)", SpatialReference{})
+	if err != nil {
+		t.Fatalf("CreateFromWKT: %v", err)
+	}
+	defer point.Destroy()
+
+	comment := "checked"
+	records := []marshalRecord{
+		{
+			ID:       1 << 40,
+			Count:    7,
+			Ratio:    0.25,
+			Name:     "first",
+			Tags:     []string{"a", "b"},
+			Sizes:    []int32{1, 2, 3},
+			Weights:  []float64{0.5, 1.5},
+			Payload:  []byte{0, 1, 2, 255},
+			Updated:  time.Date(2024, 5, 17, 10, 30, 15, 0, time.UTC),
+			Comment:  &comment,
+			Location: point,
+			Ignored:  "skip",
+		},
+		{ID: 2, Name: "second"},
+	}
+	for _, record := range records {
+		feature := layer.Definition().Create()
+		if err := feature.Marshal(record); err != nil {
+			feature.Destroy()
+			t.Fatalf("Marshal: %v", err)
+		}
+		err := layer.Create(feature)
+		feature.Destroy()
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := ReadAll[marshalRecord](layer)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(got) != len(records) {
+		t.Fatalf("ReadAll returned %d records, want %d", len(got), len(records))
+	}
+	defer func() {
+		for _, record := range got {
+			if !record.Location.IsNull() {
+				record.Location.Destroy()
+			}
+		}
+	}()
+
+	first := got[0]
+	if first.Location.IsNull() || !first.Location.Equals(point) {
+		t.Error("geometry did not round trip")
+	}
+	if first.Comment == nil || *first.Comment != comment {
+		t.Errorf("Comment = %v, want %q", first.Comment, comment)
+	}
+	if first.Ignored != "" {
+		t.Errorf("ignored field was read: %q", first.Ignored)
+	}
+	want := records[0]
+	first.Location, want.Location = Geometry{}, Geometry{}
+	first.Comment, want.Comment = nil, nil
+	want.Ignored = ""
+	if !reflect.DeepEqual(first, want) {
+		t.Errorf("record = %+v, want %+v", first, want)
+	}
+
+	second := got[1]
+	if second.Comment != nil {
+		t.Errorf("null Comment = %q, want nil", *second.Comment)
+	}
+	if !second.Location.IsNull() {
+		t.Error("missing geometry read as non-null")
+	}
+	if second.ID != 2 || second.Name != "second" || second.Tags != nil {
+		t.Errorf("second record = %+v", second)
+	}
+	reused := layer.Definition().Create()
+	defer reused.Destroy()
+	if err := reused.Marshal(records[0]); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := reused.Marshal(records[1]); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !reused.Geometry().IsNull() {
+		t.Error("null geometry did not clear the geometry of a reused feature")
+	}
+}
+
+func TestFeatureMarshalErrors(t *testing.T) {
+	ds, layer := createMemoryVectorLayer(t, "marshalerrors")
+	defer ds.Destroy()
+
+	addLayerField(t, layer, "small", FT_Integer)
+
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+
+	if err := feature.Marshal(struct {
+		Missing int `ogr:"missing"`
+	}{}); err == nil {
+		t.Error("Marshal with an unknown tagged field succeeded")
+	}
+	if err := feature.Marshal(struct{ Missing int }{}); err != nil {
+		t.Errorf("Marshal with an unknown untagged field: %v", err)
+	}
+	if err := feature.Marshal(42); err == nil {
+		t.Error("Marshal of a non-struct succeeded")
+	}
+
+	feature.SetFieldInteger(0, 300)
+	var narrow struct {
+		Small int8 `ogr:"small"`
+	}
+	if err := feature.Unmarshal(&narrow); err == nil {
+		t.Error("Unmarshal of an overflowing value succeeded")
+	}
+	var wide struct {
+		Small int `ogr:"small"`
+	}
+	if err := feature.Unmarshal(wide); err == nil {
+		t.Error("Unmarshal into a non-pointer succeeded")
+	}
+	if err := feature.Unmarshal(&wide); err != nil || wide.Small != 300 {
+		t.Errorf("Unmarshal = %d, %v, want 300", wide.Small, err)
+	}
+
+	if err := layer.CreateSchemaFromStruct(struct {
+		Unsupported map[string]int
+	}{}); err == nil {
+		t.Error("CreateSchemaFromStruct with an unsupported type succeeded")
+	}
+}
@@ -0,0 +1,221 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_ogr_upsert.h"
+*/
+import "C"
+import (
+	"fmt"
+)
+
+// DefaultFeatureBatchSize is the number of features committed per
+// transaction when FeatureWriterOptions.BatchSize is zero.
+const DefaultFeatureBatchSize = 1000
+
+// Upsert inserts the feature, or replaces the feature of the layer with the
+// same FID. Requires GDAL 3.6 or newer.
+func (layer Layer) Upsert(feature Feature) error {
+	var err C.OGRErr
+	if C.go_OGR_L_UpsertFeature(layer.cval, feature.cval, &err) == -1 {
+		return fmt.Errorf("error: upsert requires GDAL 3.6 or newer")
+	}
+	return ErrFromOGRErr(err)
+}
+
+// FeatureWriterOptions configures a FeatureWriter.
+type FeatureWriterOptions struct {
+	// BatchSize is the number of features written per transaction,
+	// DefaultFeatureBatchSize when zero.
+	BatchSize int
+	// Upsert replaces features with an existing FID instead of failing.
+	Upsert bool
+}
+
+// FeatureError is a failure to write a single feature.
+type FeatureError struct {
+	// Index is the position of the feature among those passed to Write,
+	// counted from 0.
+	Index int64
+	// FID is the feature ID, or -1 when the feature had none.
+	FID int64
+	Err error
+}
+
+func (err FeatureError) Error() string {
+	return fmt.Sprintf("error: feature %d (FID %d): %v", err.Index, err.FID, err.Err)
+}
+
+func (err FeatureError) Unwrap() error {
+	return err.Err
+}
+
+// FeatureWriter writes features to a layer, grouping them in transactions
+// of BatchSize features when the layer supports transactions. A feature the
+// layer rejects is recorded in Failures and does not stop the writer: the
+// transaction is rolled back and the rest of the batch written again, since
+// some drivers abort the whole transaction on a failed statement.
+type FeatureWriter struct {
+	layer         Layer
+	opts          FeatureWriterOptions
+	transactions  bool
+	inTransaction bool
+	// batch holds copies of the features written in the current
+	// transaction, to write them again after a rollback.
+	batch    []batchedFeature
+	count    int64
+	written  int64
+	failures []FeatureError
+}
+
+type batchedFeature struct {
+	index   int64
+	feature Feature
+}
+
+// NewFeatureWriter returns a writer adding features to layer. Close must be
+// called to commit the last batch.
+func NewFeatureWriter(layer Layer, opts FeatureWriterOptions) (*FeatureWriter, error) {
+	if opts.BatchSize < 0 {
+		return nil, fmt.Errorf("error: invalid batch size %d", opts.BatchSize)
+	}
+	if opts.Upsert && VERSION_NUM < 3060000 {
+		return nil, fmt.Errorf("error: upsert requires GDAL 3.6 or newer")
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = DefaultFeatureBatchSize
+	}
+	return &FeatureWriter{
+		layer:        layer,
+		opts:         opts,
+		transactions: layer.TestCapability(OLCTransactions),
+	}, nil
+}
+
+// Write writes the feature, starting or committing a transaction as
+// needed. The feature is copied and remains owned by the caller. Only
+// transaction failures are returned; rejected features, and the features of
+// a transaction that failed, are recorded in Failures.
+func (writer *FeatureWriter) Write(feature Feature) error {
+	if writer.transactions && !writer.inTransaction {
+		if err := writer.layer.StartTransaction(); err != nil {
+			return fmt.Errorf("error: starting transaction: %v", err)
+		}
+		writer.inTransaction = true
+	}
+	index := writer.count
+	writer.count++
+
+	if !writer.transactions {
+		if err := writer.write(feature); err != nil {
+			writer.failures = append(writer.failures, FeatureError{Index: index, FID: feature.FID(), Err: err})
+		} else {
+			writer.written++
+		}
+		return nil
+	}
+
+	entry := batchedFeature{index: index, feature: feature.Clone()}
+	if err := writer.write(feature); err != nil {
+		writer.failures = append(writer.failures, FeatureError{Index: index, FID: entry.feature.FID(), Err: err})
+		entry.feature.Destroy()
+		if err := writer.replay(); err != nil {
+			return err
+		}
+	} else {
+		writer.batch = append(writer.batch, entry)
+	}
+
+	if len(writer.batch) >= writer.opts.BatchSize {
+		return writer.Flush()
+	}
+	return nil
+}
+
+func (writer *FeatureWriter) write(feature Feature) error {
+	if writer.opts.Upsert {
+		return writer.layer.Upsert(feature)
+	}
+	return writer.layer.Create(feature)
+}
+
+// replay rolls back the current transaction and writes its batch again in a
+// new one. A feature failing again is recorded in Failures and dropped from
+// the batch before the next attempt.
+func (writer *FeatureWriter) replay() error {
+	for {
+		writer.inTransaction = false
+		if err := writer.layer.RollbackTransaction(); err != nil {
+			return writer.abort(fmt.Errorf("error: rolling back transaction: %v", err))
+		}
+		if err := writer.layer.StartTransaction(); err != nil {
+			return writer.abort(fmt.Errorf("error: starting transaction: %v", err))
+		}
+		writer.inTransaction = true
+
+		failed := -1
+		for i, entry := range writer.batch {
+			feature := entry.feature.Clone()
+			err := writer.write(feature)
+			feature.Destroy()
+			if err != nil {
+				writer.failures = append(writer.failures, FeatureError{Index: entry.index, FID: entry.feature.FID(), Err: err})
+				failed = i
+				break
+			}
+		}
+		if failed < 0 {
+			return nil
+		}
+		writer.batch[failed].feature.Destroy()
+		writer.batch = append(writer.batch[:failed], writer.batch[failed+1:]...)
+	}
+}
+
+// abort records the features of the current batch as failed with err and
+// returns err.
+func (writer *FeatureWriter) abort(err error) error {
+	for _, entry := range writer.batch {
+		writer.failures = append(writer.failures, FeatureError{Index: entry.index, FID: entry.feature.FID(), Err: err})
+	}
+	writer.clearBatch()
+	return err
+}
+
+func (writer *FeatureWriter) clearBatch() {
+	for _, entry := range writer.batch {
+		entry.feature.Destroy()
+	}
+	writer.batch = nil
+}
+
+// Flush commits the current transaction, if any. When the commit fails, the
+// features of the transaction are recorded in Failures.
+func (writer *FeatureWriter) Flush() error {
+	if !writer.inTransaction {
+		return nil
+	}
+	writer.inTransaction = false
+	if err := writer.layer.CommitTransaction(); err != nil {
+		return writer.abort(fmt.Errorf("error: committing transaction: %v", err))
+	}
+	writer.written += int64(len(writer.batch))
+	writer.clearBatch()
+	return nil
+}
+
+// Close commits the remaining features. The layer is not closed.
+func (writer *FeatureWriter) Close() error {
+	return writer.Flush()
+}
+
+// Written returns the number of features written and committed.
+func (writer *FeatureWriter) Written() int64 {
+	return writer.written
+}
+
+// Failures returns the features the layer rejected or that were lost with a
+// failed transaction.
+func (writer *FeatureWriter) Failures() []FeatureError {
+	return writer.failures
+}
@@ -0,0 +1,126 @@
+package gdal
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestFeatureWriterTransactions(t *testing.T) {
+	filename := "./tmp/featurewriter.gpkg"
+	os.Remove(filename)
+	defer os.Remove(filename)
+
+	ds, ok := OGRDriverByName(OGRDriverNameGPKG).Create(filename, nil)
+	if !ok {
+		t.Fatal("failed to create GPKG datasource")
+	}
+	defer ds.Destroy()
+
+	layer := ds.CreateLayer("points", SpatialReference{}, GT_Point, nil)
+	if layer.cval == nil {
+		t.Fatal("failed to create GPKG layer")
+	}
+	addLayerField(t, layer, "value", FT_Integer)
+
+	writer, err := NewFeatureWriter(layer, FeatureWriterOptions{BatchSize: 10})
+	if err != nil {
+		t.Fatalf("NewFeatureWriter: %v", err)
+	}
+	if !writer.transactions {
+		t.Fatal("GPKG layer should use transactions")
+	}
+
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+	for i := 0; i < 25; i++ {
+		feature.SetFID(int64(i + 1))
+		feature.SetFieldInteger(0, i)
+		if err := writer.Write(feature); err != nil {
+			t.Fatalf("Write(%d): %v", i, err)
+		}
+		if i == 14 && writer.Written() != 10 {
+			t.Errorf("Written after 15 features = %d, want 10", writer.Written())
+		}
+	}
+
+	// A duplicate FID is rejected without losing the rest of the batch.
+	feature.SetFID(3)
+	if err := writer.Write(feature); err != nil {
+		t.Fatalf("Write(duplicate): %v", err)
+	}
+	feature.SetFID(26)
+	if err := writer.Write(feature); err != nil {
+		t.Fatalf("Write(26): %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if writer.Written() != 26 {
+		t.Errorf("Written = %d, want 26", writer.Written())
+	}
+	failures := writer.Failures()
+	if len(failures) != 1 {
+		t.Fatalf("Failures = %v, want one", failures)
+	}
+	if failures[0].Index != 25 || failures[0].FID != 3 {
+		t.Errorf("failure = %+v, want index 25 and FID 3", failures[0])
+	}
+	var featureErr FeatureError
+	if !errors.As(error(failures[0]), &featureErr) || featureErr.Unwrap() == nil {
+		t.Error("FeatureError does not wrap the driver error")
+	}
+	if count, _ := layer.FeatureCount(true); count != 26 {
+		t.Errorf("FeatureCount = %d, want 26", count)
+	}
+}
+
+func TestFeatureWriterUpsert(t *testing.T) {
+	ds, layer := createPointLayer(t, 3)
+	defer ds.Destroy()
+
+	writer, err := NewFeatureWriter(layer, FeatureWriterOptions{Upsert: true})
+	if VERSION_NUM < 3060000 {
+		if err == nil {
+			t.Error("NewFeatureWriter accepted upsert before GDAL 3.6")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("NewFeatureWriter: %v", err)
+	}
+	if writer.transactions {
+		t.Error("Memory layer should not use transactions")
+	}
+
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+	feature.SetFID(1)
+	feature.SetFieldInteger(0, 100)
+	if err := writer.Write(feature); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if failures := writer.Failures(); len(failures) != 0 {
+		t.Fatalf("Failures = %v", failures)
+	}
+	if count, _ := layer.FeatureCount(true); count != 3 {
+		t.Errorf("FeatureCount = %d, want 3", count)
+	}
+	if got := collectValues(t, layer); got[1] != 100 {
+		t.Errorf("values = %v, want FID 1 replaced by 100", got)
+	}
+}
+
+func TestNewFeatureWriterInvalidBatchSize(t *testing.T) {
+	ds, layer := createMemoryVectorLayer(t, "invalid")
+	defer ds.Destroy()
+
+	if _, err := NewFeatureWriter(layer, FeatureWriterOptions{BatchSize: -1}); err == nil {
+		t.Error("NewFeatureWriter accepted a negative batch size")
+	}
+}
@@ -0,0 +1,132 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_ogr_field.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// FieldSubType refines the interpretation of a FieldType.
+type FieldSubType int
+
+// FST_None and related constants are exported GDAL/OGR symbols.
+const (
+	FST_None    = FieldSubType(C.OFSTNone)
+	FST_Boolean = FieldSubType(C.OFSTBoolean)
+	FST_Int16   = FieldSubType(C.OFSTInt16)
+	FST_Float32 = FieldSubType(C.OFSTFloat32)
+	FST_JSON    = FieldSubType(C.OFSTJSON)
+	FST_UUID    = FieldSubType(C.OFSTUUID)
+)
+
+// Name returns human readable name for the field subtype.
+func (fst FieldSubType) Name() string {
+	return C.GoString(C.OGR_GetFieldSubTypeName(C.OGRFieldSubType(fst)))
+}
+
+// SubType returns the subtype of the field.
+func (fd FieldDefinition) SubType() FieldSubType {
+	return FieldSubType(C.OGR_Fld_GetSubType(fd.cval))
+}
+
+// SetSubType sets the subtype of the field. A subtype not compatible with
+// the field type is replaced by FST_None.
+func (fd FieldDefinition) SetSubType(subType FieldSubType) {
+	C.OGR_Fld_SetSubType(fd.cval, C.OGRFieldSubType(subType))
+}
+
+// IsNullable returns whether the field accepts null values.
+func (fd FieldDefinition) IsNullable() bool {
+	return C.OGR_Fld_IsNullable(fd.cval) != 0
+}
+
+// SetNullable sets whether the field accepts null values. Fields are
+// nullable by default.
+func (fd FieldDefinition) SetNullable(nullable bool) {
+	C.OGR_Fld_SetNullable(fd.cval, BoolToCInt(nullable))
+}
+
+// IsUnique returns whether the field has a unique constraint.
+func (fd FieldDefinition) IsUnique() bool {
+	return C.OGR_Fld_IsUnique(fd.cval) != 0
+}
+
+// SetUnique sets whether the field has a unique constraint.
+func (fd FieldDefinition) SetUnique(unique bool) {
+	C.OGR_Fld_SetUnique(fd.cval, BoolToCInt(unique))
+}
+
+// Default returns the default value of the field as an SQL literal, or an
+// empty string when it has none.
+func (fd FieldDefinition) Default() string {
+	return C.GoString(C.OGR_Fld_GetDefault(fd.cval))
+}
+
+// SetDefault sets the default value of the field as an SQL literal: a
+// number, a quoted string such as 'text', NULL, CURRENT_TIMESTAMP,
+// CURRENT_DATE, CURRENT_TIME or a driver specific expression. An empty
+// string removes the default.
+func (fd FieldDefinition) SetDefault(value string) {
+	if value == "" {
+		C.OGR_Fld_SetDefault(fd.cval, nil)
+		return
+	}
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+	C.OGR_Fld_SetDefault(fd.cval, cValue)
+}
+
+// IsDefaultDriverSpecific returns whether the default value is a driver
+// specific expression rather than a literal or CURRENT_* keyword.
+func (fd FieldDefinition) IsDefaultDriverSpecific() bool {
+	return C.OGR_Fld_IsDefaultDriverSpecific(fd.cval) != 0
+}
+
+// AlternativeName returns the alternative name, or alias, of the field.
+func (fd FieldDefinition) AlternativeName() string {
+	return C.GoString(C.OGR_Fld_GetAlternativeNameRef(fd.cval))
+}
+
+// SetAlternativeName sets the alternative name, or alias, of the field.
+func (fd FieldDefinition) SetAlternativeName(name string) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.OGR_Fld_SetAlternativeName(fd.cval, cName)
+}
+
+// Comment returns the comment of the field. It is always empty before
+// GDAL 3.7.
+func (fd FieldDefinition) Comment() string {
+	return C.GoString(C.go_OGR_Fld_GetComment(fd.cval))
+}
+
+// SetComment sets the comment of the field. Requires GDAL 3.7 or newer.
+func (fd FieldDefinition) SetComment(comment string) error {
+	cComment := C.CString(comment)
+	defer C.free(unsafe.Pointer(cComment))
+	if C.go_OGR_Fld_SetComment(fd.cval, cComment) == -1 {
+		return fmt.Errorf("error: field comments require GDAL 3.7 or newer")
+	}
+	return nil
+}
+
+// IsFieldNull returns whether the field has been set to null, which is
+// distinct from being unset.
+func (feature Feature) IsFieldNull(index int) bool {
+	return C.OGR_F_IsFieldNull(feature.cval, C.int(index)) != 0
+}
+
+// IsFieldSetAndNotNull returns whether the field has a value.
+func (feature Feature) IsFieldSetAndNotNull(index int) bool {
+	return C.OGR_F_IsFieldSetAndNotNull(feature.cval, C.int(index)) != 0
+}
+
+// SetFieldNull sets the field to null. Unlike an unset field, a null field
+// is written as NULL rather than receiving the field default.
+func (feature Feature) SetFieldNull(index int) {
+	C.OGR_F_SetFieldNull(feature.cval, C.int(index))
+}
@@ -0,0 +1,129 @@
+package gdal
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFieldDefinitionProperties(t *testing.T) {
+	fd := CreateFieldDefinition("flag", FT_Integer)
+	defer fd.Destroy()
+
+	if !fd.IsNullable() || fd.IsUnique() || fd.Default() != "" || fd.SubType() != FST_None {
+		t.Fatal("unexpected defaults for a new field definition")
+	}
+
+	fd.SetSubType(FST_Boolean)
+	fd.SetNullable(false)
+	fd.SetUnique(true)
+	fd.SetDefault("1")
+	fd.SetAlternativeName("Flag")
+
+	if fd.SubType() != FST_Boolean {
+		t.Errorf("SubType = %s, want %s", fd.SubType().Name(), FST_Boolean.Name())
+	}
+	if fd.IsNullable() {
+		t.Error("IsNullable = true after SetNullable(false)")
+	}
+	if !fd.IsUnique() {
+		t.Error("IsUnique = false after SetUnique(true)")
+	}
+	if fd.Default() != "1" || fd.IsDefaultDriverSpecific() {
+		t.Errorf("Default = %q, want literal 1", fd.Default())
+	}
+	if fd.AlternativeName() != "Flag" {
+		t.Errorf("AlternativeName = %q, want Flag", fd.AlternativeName())
+	}
+
+	fd.SetDefault("")
+	if fd.Default() != "" {
+		t.Errorf("Default = %q after clearing", fd.Default())
+	}
+
+	// A subtype that does not fit the field type is dropped.
+	fd.SetSubType(FST_UUID)
+	if fd.SubType() != FST_None {
+		t.Errorf("SubType = %s, want None for an integer field", fd.SubType().Name())
+	}
+
+	err := fd.SetComment("a comment")
+	if VERSION_NUM < 3070000 {
+		if err == nil {
+			t.Error("SetComment succeeded before GDAL 3.7")
+		}
+		if fd.Comment() != "" {
+			t.Errorf("Comment = %q before GDAL 3.7", fd.Comment())
+		}
+	} else if err != nil || fd.Comment() != "a comment" {
+		t.Errorf("Comment = %q, %v", fd.Comment(), err)
+	}
+}
+
+func TestFieldDefinitionGPKGSchema(t *testing.T) {
+	filename := "./tmp/fielddefn.gpkg"
+	os.Remove(filename)
+	defer os.Remove(filename)
+
+	ds, ok := OGRDriverByName(OGRDriverNameGPKG).Create(filename, nil)
+	if !ok {
+		t.Fatal("failed to create GPKG datasource")
+	}
+	layer := ds.CreateLayer("items", SpatialReference{}, GT_Point, nil)
+	if layer.cval == nil {
+		ds.Destroy()
+		t.Fatal("failed to create GPKG layer")
+	}
+
+	code := CreateFieldDefinition("code", FT_String)
+	code.SetNullable(false)
+	code.SetUnique(true)
+	code.SetDefault("'none'")
+	flag := CreateFieldDefinition("flag", FT_Integer)
+	flag.SetSubType(FST_Boolean)
+	for _, fd := range []FieldDefinition{code, flag} {
+		if err := layer.CreateField(fd, false); err != nil {
+			t.Fatalf("CreateField(%s): %v", fd.Name(), err)
+		}
+		fd.Destroy()
+	}
+	ds.Destroy()
+
+	ds, ok = OGRDriverByName(OGRDriverNameGPKG).Open(filename, 0)
+	if !ok {
+		t.Fatal("failed to reopen GPKG datasource")
+	}
+	defer ds.Destroy()
+
+	definition := ds.LayerByName("items").Definition()
+	code = definition.FieldDefinition(definition.FieldIndex("code"))
+	if code.IsNullable() || !code.IsUnique() || code.Default() != "'none'" {
+		t.Errorf("code: nullable=%v unique=%v default=%q", code.IsNullable(), code.IsUnique(), code.Default())
+	}
+	flag = definition.FieldDefinition(definition.FieldIndex("flag"))
+	if flag.SubType() != FST_Boolean {
+		t.Errorf("flag subtype = %s, want Boolean", flag.SubType().Name())
+	}
+}
+
+func TestFeatureFieldNull(t *testing.T) {
+	ds, layer := createMemoryVectorLayer(t, "nulls")
+	defer ds.Destroy()
+	addLayerField(t, layer, "value", FT_Integer)
+
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+
+	if feature.IsFieldSet(0) || feature.IsFieldNull(0) || feature.IsFieldSetAndNotNull(0) {
+		t.Error("new feature field should be unset")
+	}
+
+	feature.SetFieldNull(0)
+	if !feature.IsFieldSet(0) || !feature.IsFieldNull(0) || feature.IsFieldSetAndNotNull(0) {
+		t.Error("null field should be set and null")
+	}
+
+	feature.SetFieldInteger(0, 3)
+	if feature.IsFieldNull(0) || !feature.IsFieldSetAndNotNull(0) {
+		t.Error("field with a value should be set and not null")
+	}
+}
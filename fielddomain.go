@@ -0,0 +1,564 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_ogr_fielddomain.h"
+#include <cpl_string.h>
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// FieldDomainType is the kind of a field domain.
+type FieldDomainType int
+
+// FDT_Coded and related constants are exported GDAL/OGR symbols.
+const (
+	FDT_Coded = FieldDomainType(C.OFDT_CODED)
+	FDT_Range = FieldDomainType(C.OFDT_RANGE)
+	FDT_Glob  = FieldDomainType(C.OFDT_GLOB)
+)
+
+// FieldDomainSplitPolicy tells how a field value is set when a feature is
+// split.
+type FieldDomainSplitPolicy int
+
+// OFDSP_DefaultValue and related constants are exported GDAL/OGR symbols.
+const (
+	OFDSP_DefaultValue  = FieldDomainSplitPolicy(C.OFDSP_DEFAULT_VALUE)
+	OFDSP_Duplicate     = FieldDomainSplitPolicy(C.OFDSP_DUPLICATE)
+	OFDSP_GeometryRatio = FieldDomainSplitPolicy(C.OFDSP_GEOMETRY_RATIO)
+)
+
+// FieldDomainMergePolicy tells how a field value is set when features are
+// merged.
+type FieldDomainMergePolicy int
+
+// OFDMP_DefaultValue and related constants are exported GDAL/OGR symbols.
+const (
+	OFDMP_DefaultValue     = FieldDomainMergePolicy(C.OFDMP_DEFAULT_VALUE)
+	OFDMP_Sum              = FieldDomainMergePolicy(C.OFDMP_SUM)
+	OFDMP_GeometryWeighted = FieldDomainMergePolicy(C.OFDMP_GEOMETRY_WEIGHTED)
+)
+
+// FieldDomain is a set of allowed values shared by fields of a dataset.
+type FieldDomain struct {
+	cval C.OGRFieldDomainH
+}
+
+// CodedValue is an allowed code of a coded domain and its description.
+type CodedValue struct {
+	Code  string
+	Value string
+}
+
+// RangeBound is a bound of a range domain. Value is an int64 for integer
+// domains, a float64 for real domains, a time.Time for date time domains,
+// and nil for an unbounded side.
+type RangeBound struct {
+	Value     interface{}
+	Inclusive bool
+}
+
+// CreateCodedFieldDomain creates a domain restricting fields to the codes
+// of values. An empty Value leaves a code without description.
+func CreateCodedFieldDomain(
+	name, description string,
+	fieldType FieldType,
+	subType FieldSubType,
+	values []CodedValue,
+) (FieldDomain, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cDescription := C.CString(description)
+	defer C.free(unsafe.Pointer(cDescription))
+
+	length := len(values)
+	codes := make([]*C.char, length+1)
+	descriptions := make([]*C.char, length+1)
+	for i, value := range values {
+		codes[i] = C.CString(value.Code)
+		defer C.free(unsafe.Pointer(codes[i]))
+		if value.Value != "" {
+			descriptions[i] = C.CString(value.Value)
+			defer C.free(unsafe.Pointer(descriptions[i]))
+		}
+	}
+
+	domain := C.go_CodedFldDomain_Create(
+		cName,
+		cDescription,
+		C.OGRFieldType(fieldType),
+		C.OGRFieldSubType(subType),
+		(**C.char)(unsafe.Pointer(&codes[0])),
+		(**C.char)(unsafe.Pointer(&descriptions[0])),
+		C.int(length),
+	)
+	if domain == nil {
+		return FieldDomain{}, fmt.Errorf("error: failed to create coded domain %q", name)
+	}
+	return FieldDomain{domain}, nil
+}
+
+// setOGRField stores the bound value into field according to fieldType.
+func setOGRField(field *C.OGRField, fieldType FieldType, value interface{}) error {
+	switch fieldType {
+	case FT_Integer, FT_Integer64:
+		var n int64
+		switch v := value.(type) {
+		case int:
+			n = int64(v)
+		case int64:
+			n = v
+		default:
+			return fmt.Errorf("error: integer domain bound must be an int64, got %T", value)
+		}
+		if fieldType == FT_Integer {
+			if n < math.MinInt32 || n > math.MaxInt32 {
+				return fmt.Errorf("error: domain bound %d overflows a 32-bit integer field", n)
+			}
+			C.go_OGRField_SetInteger(field, C.int(n))
+		} else {
+			C.go_OGRField_SetInteger64(field, C.GIntBig(n))
+		}
+	case FT_Real:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("error: real domain bound must be a float64, got %T", value)
+		}
+		C.go_OGRField_SetReal(field, C.double(v))
+	case FT_DateTime:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("error: date time domain bound must be a time.Time, got %T", value)
+		}
+		// Keep the time zone offset when OGR can represent it, in 15 minute
+		// steps after 100 for GMT.
+		_, offset := v.Zone()
+		if offset%900 != 0 {
+			v, offset = v.UTC(), 0
+		}
+		seconds := float64(v.Second()) + float64(v.Nanosecond())/1e9
+		C.go_OGRField_SetDateTime(
+			field,
+			C.int(v.Year()), C.int(v.Month()), C.int(v.Day()),
+			C.int(v.Hour()), C.int(v.Minute()), C.float(seconds),
+			C.int(100+offset/900),
+		)
+	default:
+		return fmt.Errorf("error: range domains do not support %s fields", fieldType.Name())
+	}
+	return nil
+}
+
+// CreateRangeFieldDomain creates a domain restricting fields to the range
+// between min and max.
+func CreateRangeFieldDomain(
+	name, description string,
+	fieldType FieldType,
+	subType FieldSubType,
+	min, max RangeBound,
+) (FieldDomain, error) {
+	// A nil bound is passed as NULL, leaving that side unbounded.
+	var cMin, cMax *C.OGRField
+	if min.Value != nil {
+		cMin = new(C.OGRField)
+		if err := setOGRField(cMin, fieldType, min.Value); err != nil {
+			return FieldDomain{}, err
+		}
+	}
+	if max.Value != nil {
+		cMax = new(C.OGRField)
+		if err := setOGRField(cMax, fieldType, max.Value); err != nil {
+			return FieldDomain{}, err
+		}
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cDescription := C.CString(description)
+	defer C.free(unsafe.Pointer(cDescription))
+
+	domain := C.OGR_RangeFldDomain_Create(
+		cName,
+		cDescription,
+		C.OGRFieldType(fieldType),
+		C.OGRFieldSubType(subType),
+		cMin,
+		C.bool(min.Inclusive),
+		cMax,
+		C.bool(max.Inclusive),
+	)
+	if domain == nil {
+		return FieldDomain{}, fmt.Errorf("error: failed to create range domain %q", name)
+	}
+	return FieldDomain{domain}, nil
+}
+
+// CreateGlobFieldDomain creates a domain restricting fields to values
+// matching glob, where * matches any run of characters, ? a single
+// character and [...] a character class.
+func CreateGlobFieldDomain(
+	name, description string,
+	fieldType FieldType,
+	subType FieldSubType,
+	glob string,
+) (FieldDomain, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cDescription := C.CString(description)
+	defer C.free(unsafe.Pointer(cDescription))
+	cGlob := C.CString(glob)
+	defer C.free(unsafe.Pointer(cGlob))
+
+	domain := C.OGR_GlobFldDomain_Create(
+		cName,
+		cDescription,
+		C.OGRFieldType(fieldType),
+		C.OGRFieldSubType(subType),
+		cGlob,
+	)
+	if domain == nil {
+		return FieldDomain{}, fmt.Errorf("error: failed to create glob domain %q", name)
+	}
+	return FieldDomain{domain}, nil
+}
+
+// Destroy the field domain. Domains returned by Dataset.FieldDomain are
+// owned by the dataset and must not be destroyed.
+func (domain FieldDomain) Destroy() {
+	C.OGR_FldDomain_Destroy(domain.cval)
+}
+
+// Name returns the name of the domain.
+func (domain FieldDomain) Name() string {
+	return C.GoString(C.OGR_FldDomain_GetName(domain.cval))
+}
+
+// Description returns the description of the domain.
+func (domain FieldDomain) Description() string {
+	return C.GoString(C.OGR_FldDomain_GetDescription(domain.cval))
+}
+
+// Type returns the kind of the domain.
+func (domain FieldDomain) Type() FieldDomainType {
+	return FieldDomainType(C.OGR_FldDomain_GetDomainType(domain.cval))
+}
+
+// FieldType returns the type of the fields the domain applies to.
+func (domain FieldDomain) FieldType() FieldType {
+	return FieldType(C.OGR_FldDomain_GetFieldType(domain.cval))
+}
+
+// FieldSubType returns the subtype of the fields the domain applies to.
+func (domain FieldDomain) FieldSubType() FieldSubType {
+	return FieldSubType(C.OGR_FldDomain_GetFieldSubType(domain.cval))
+}
+
+// SplitPolicy returns the split policy of the domain.
+func (domain FieldDomain) SplitPolicy() FieldDomainSplitPolicy {
+	return FieldDomainSplitPolicy(C.OGR_FldDomain_GetSplitPolicy(domain.cval))
+}
+
+// SetSplitPolicy sets the split policy of the domain.
+func (domain FieldDomain) SetSplitPolicy(policy FieldDomainSplitPolicy) {
+	C.OGR_FldDomain_SetSplitPolicy(domain.cval, C.OGRFieldDomainSplitPolicy(policy))
+}
+
+// MergePolicy returns the merge policy of the domain.
+func (domain FieldDomain) MergePolicy() FieldDomainMergePolicy {
+	return FieldDomainMergePolicy(C.OGR_FldDomain_GetMergePolicy(domain.cval))
+}
+
+// SetMergePolicy sets the merge policy of the domain.
+func (domain FieldDomain) SetMergePolicy(policy FieldDomainMergePolicy) {
+	C.OGR_FldDomain_SetMergePolicy(domain.cval, C.OGRFieldDomainMergePolicy(policy))
+}
+
+// CodedValues returns the codes of a coded domain.
+func (domain FieldDomain) CodedValues() []CodedValue {
+	if domain.Type() != FDT_Coded {
+		return nil
+	}
+	var values []CodedValue
+	ptr := uintptr(unsafe.Pointer(C.OGR_CodedFldDomain_GetEnumeration(domain.cval)))
+	for {
+		value := (*C.OGRCodedValue)(unsafe.Pointer(ptr))
+		if value.pszCode == nil {
+			break
+		}
+		values = append(values, CodedValue{C.GoString(value.pszCode), C.GoString(value.pszValue)})
+		ptr += unsafe.Sizeof(*value)
+	}
+	return values
+}
+
+// rangeBound converts a bound of a range domain.
+func (domain FieldDomain) rangeBound(field *C.OGRField, inclusive C.bool) RangeBound {
+	bound := RangeBound{Inclusive: bool(inclusive)}
+	if C.go_OGRField_IsUnset(field) != 0 {
+		return bound
+	}
+	switch domain.FieldType() {
+	case FT_Integer:
+		bound.Value = int64(C.go_OGRField_GetInteger(field))
+	case FT_Integer64:
+		bound.Value = int64(C.go_OGRField_GetInteger64(field))
+	case FT_Real:
+		bound.Value = float64(C.go_OGRField_GetReal(field))
+	case FT_DateTime:
+		var year, month, day, hour, minute, tzFlag C.int
+		var second C.float
+		C.go_OGRField_GetDateTime(field, &year, &month, &day, &hour, &minute, &second, &tzFlag)
+		whole, fraction := math.Modf(float64(second))
+		bound.Value = time.Date(int(year), time.Month(month), int(day), int(hour), int(minute),
+			int(whole), int(math.Round(fraction*1e9)), ogrTimeZone(int(tzFlag)))
+	}
+	return bound
+}
+
+// ogrTimeZone returns the location of an OGR time zone flag: 1 for local
+// time, 100 for GMT and offsets from GMT in 15 minute steps around 100. An
+// unknown time zone is read as UTC.
+func ogrTimeZone(tzFlag int) *time.Location {
+	switch {
+	case tzFlag == 1:
+		return time.Local
+	case tzFlag <= 1 || tzFlag == 100:
+		return time.UTC
+	default:
+		return time.FixedZone("", (tzFlag-100)*15*60)
+	}
+}
+
+// Min returns the lower bound of a range domain.
+func (domain FieldDomain) Min() RangeBound {
+	if domain.Type() != FDT_Range {
+		return RangeBound{}
+	}
+	var inclusive C.bool
+	field := C.OGR_RangeFldDomain_GetMin(domain.cval, &inclusive)
+	return domain.rangeBound(field, inclusive)
+}
+
+// Max returns the upper bound of a range domain.
+func (domain FieldDomain) Max() RangeBound {
+	if domain.Type() != FDT_Range {
+		return RangeBound{}
+	}
+	var inclusive C.bool
+	field := C.OGR_RangeFldDomain_GetMax(domain.cval, &inclusive)
+	return domain.rangeBound(field, inclusive)
+}
+
+// Glob returns the pattern of a glob domain.
+func (domain FieldDomain) Glob() string {
+	if domain.Type() != FDT_Glob {
+		return ""
+	}
+	return C.GoString(C.OGR_GlobFldDomain_GetGlob(domain.cval))
+}
+
+// globRegexp translates a glob domain pattern to a regular expression.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			pattern.WriteString("(?s:.*)")
+		case '?':
+			pattern.WriteString("(?s:.)")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("error: unterminated character class in glob %q", glob)
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			pattern.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	pattern.WriteString("$")
+	return regexp.Compile(pattern.String())
+}
+
+// inRange reports whether value lies within bound, on the lower side when
+// lower is true.
+func inRange(value interface{}, bound RangeBound, lower bool) bool {
+	var cmp int
+	switch b := bound.Value.(type) {
+	case nil:
+		return true
+	case int64:
+		cmp = compareFloat(value.(float64), float64(b))
+	case float64:
+		cmp = compareFloat(value.(float64), b)
+	case time.Time:
+		cmp = value.(time.Time).Compare(b)
+	}
+	if cmp == 0 {
+		return bound.Inclusive
+	}
+	return (cmp > 0) == lower
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Validate checks the value of the field at index of feature against the
+// domain. Unset and null values are valid.
+func (domain FieldDomain) Validate(feature Feature, index int) error {
+	if !feature.IsFieldSetAndNotNull(index) {
+		return nil
+	}
+	name := feature.FieldDefinition(index).Name()
+	switch domain.Type() {
+	case FDT_Coded:
+		value := feature.FieldAsString(index)
+		for _, coded := range domain.CodedValues() {
+			if coded.Code == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("error: value %q of field %q is not a code of domain %q", value, name, domain.Name())
+	case FDT_Range:
+		var value interface{}
+		if domain.FieldType() == FT_DateTime {
+			t, ok := feature.FieldAsDateTime(index)
+			if !ok {
+				return fmt.Errorf("error: field %q is not a date", name)
+			}
+			value = t
+		} else {
+			value = feature.FieldAsFloat64(index)
+		}
+		if !inRange(value, domain.Min(), true) || !inRange(value, domain.Max(), false) {
+			return fmt.Errorf("error: value %s of field %q is out of the range of domain %q",
+				feature.FieldAsString(index), name, domain.Name())
+		}
+	case FDT_Glob:
+		re, err := globRegexp(domain.Glob())
+		if err != nil {
+			return err
+		}
+		if value := feature.FieldAsString(index); !re.MatchString(value) {
+			return fmt.Errorf("error: value %q of field %q does not match domain %q", value, name, domain.Name())
+		}
+	}
+	return nil
+}
+
+// DomainName returns the name of the domain of the field, or an empty
+// string.
+func (fd FieldDefinition) DomainName() string {
+	return C.GoString(C.OGR_Fld_GetDomainName(fd.cval))
+}
+
+// SetDomainName links the field to the named domain of its dataset. An
+// empty name removes the link.
+func (fd FieldDefinition) SetDomainName(name string) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.OGR_Fld_SetDomainName(fd.cval, cName)
+}
+
+// FieldDomainNames returns the names of the field domains of the dataset.
+// Requires GDAL 3.5 or newer.
+func (dataset Dataset) FieldDomainNames() ([]string, error) {
+	var names **C.char
+	if C.go_GDALDatasetGetFieldDomainNames(dataset.cval, &names) == -1 {
+		return nil, fmt.Errorf("error: listing field domains requires GDAL 3.5 or newer")
+	}
+	defer C.CSLDestroy(names)
+	return cStringListToSlice(names), nil
+}
+
+// FieldDomain returns the named field domain, owned by the dataset.
+func (dataset Dataset) FieldDomain(name string) (FieldDomain, bool) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	domain := C.GDALDatasetGetFieldDomain(dataset.cval, cName)
+	return FieldDomain{domain}, domain != nil
+}
+
+// AddFieldDomain adds a copy of domain to the dataset.
+func (dataset Dataset) AddFieldDomain(domain FieldDomain) error {
+	var reason *C.char
+	if !C.GDALDatasetAddFieldDomain(dataset.cval, domain.cval, &reason) {
+		return fmt.Errorf("error: adding field domain %q: %s", domain.Name(), goStringAndCPLFree(reason))
+	}
+	return nil
+}
+
+// UpdateFieldDomain replaces the dataset domain of the same name by a copy
+// of domain. Requires GDAL 3.5 or newer.
+func (dataset Dataset) UpdateFieldDomain(domain FieldDomain) error {
+	var reason *C.char
+	switch C.go_GDALDatasetUpdateFieldDomain(dataset.cval, domain.cval, &reason) {
+	case 1:
+		return nil
+	case -1:
+		return fmt.Errorf("error: updating field domains requires GDAL 3.5 or newer")
+	default:
+		return fmt.Errorf("error: updating field domain %q: %s", domain.Name(), goStringAndCPLFree(reason))
+	}
+}
+
+// DeleteFieldDomain deletes the named domain from the dataset. Requires
+// GDAL 3.5 or newer.
+func (dataset Dataset) DeleteFieldDomain(name string) error {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var reason *C.char
+	switch C.go_GDALDatasetDeleteFieldDomain(dataset.cval, cName, &reason) {
+	case 1:
+		return nil
+	case -1:
+		return fmt.Errorf("error: deleting field domains requires GDAL 3.5 or newer")
+	default:
+		return fmt.Errorf("error: deleting field domain %q: %s", name, goStringAndCPLFree(reason))
+	}
+}
+
+// ValidateFieldDomains checks every field of feature linked to a domain of
+// the dataset and returns the joined violations. Fields naming a domain
+// missing from the dataset are reported too.
+func (dataset Dataset) ValidateFieldDomains(feature Feature) error {
+	var errs []error
+	for i := 0; i < feature.FieldCount(); i++ {
+		name := feature.FieldDefinition(i).DomainName()
+		if name == "" {
+			continue
+		}
+		domain, ok := dataset.FieldDomain(name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("error: domain %q of field %q not found", name, feature.FieldDefinition(i).Name()))
+			continue
+		}
+		if err := domain.Validate(feature, i); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
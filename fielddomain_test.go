@@ -0,0 +1,222 @@
+package gdal
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFieldDomainTypes(t *testing.T) {
+	coded, err := CreateCodedFieldDomain("landuse", "Land use", FT_String, FST_None, []CodedValue{
+		{"F", "Forest"},
+		{"W", "Water"},
+		{"U", ""},
+	})
+	if err != nil {
+		t.Fatalf("CreateCodedFieldDomain: %v", err)
+	}
+	defer coded.Destroy()
+
+	if coded.Type() != FDT_Coded || coded.Name() != "landuse" || coded.Description() != "Land use" {
+		t.Errorf("coded domain = %v %q %q", coded.Type(), coded.Name(), coded.Description())
+	}
+	want := []CodedValue{{"F", "Forest"}, {"W", "Water"}, {"U", ""}}
+	if got := coded.CodedValues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CodedValues = %v, want %v", got, want)
+	}
+
+	ranged, err := CreateRangeFieldDomain("percent", "", FT_Real, FST_None,
+		RangeBound{Value: 0.0, Inclusive: true}, RangeBound{Value: 100.0})
+	if err != nil {
+		t.Fatalf("CreateRangeFieldDomain: %v", err)
+	}
+	defer ranged.Destroy()
+
+	if min := ranged.Min(); min.Value != 0.0 || !min.Inclusive {
+		t.Errorf("Min = %+v, want inclusive 0", min)
+	}
+	if max := ranged.Max(); max.Value != 100.0 || max.Inclusive {
+		t.Errorf("Max = %+v, want exclusive 100", max)
+	}
+
+	unbounded, err := CreateRangeFieldDomain("positive", "", FT_Integer, FST_None,
+		RangeBound{Value: int64(1), Inclusive: true}, RangeBound{})
+	if err != nil {
+		t.Fatalf("CreateRangeFieldDomain: %v", err)
+	}
+	defer unbounded.Destroy()
+	if max := unbounded.Max(); max.Value != nil {
+		t.Errorf("Max = %+v, want unbounded", max)
+	}
+
+	if _, err := CreateRangeFieldDomain("bad", "", FT_Real, FST_None, RangeBound{Value: "x"}, RangeBound{}); err == nil {
+		t.Error("CreateRangeFieldDomain accepted a string bound")
+	}
+	if _, err := CreateRangeFieldDomain("wide", "", FT_Integer, FST_None, RangeBound{Value: int64(1) << 40}, RangeBound{}); err == nil {
+		t.Error("CreateRangeFieldDomain accepted a bound overflowing a 32-bit integer")
+	}
+
+	start := time.Date(2024, 5, 17, 10, 30, 15, 250000000, time.FixedZone("", 2*60*60))
+	dated, err := CreateRangeFieldDomain("dated", "", FT_DateTime, FST_None,
+		RangeBound{Value: start, Inclusive: true}, RangeBound{})
+	if err != nil {
+		t.Fatalf("CreateRangeFieldDomain(DateTime): %v", err)
+	}
+	defer dated.Destroy()
+	got, ok := dated.Min().Value.(time.Time)
+	if _, offset := got.Zone(); !ok || !got.Equal(start) || offset != 2*60*60 {
+		t.Errorf("DateTime Min = %v, want %v", dated.Min().Value, start)
+	}
+
+	glob, err := CreateGlobFieldDomain("code", "", FT_String, FST_None, "[A-Z]??-*")
+	if err != nil {
+		t.Fatalf("CreateGlobFieldDomain: %v", err)
+	}
+	defer glob.Destroy()
+	if glob.Glob() != "[A-Z]??-*" {
+		t.Errorf("Glob = %q", glob.Glob())
+	}
+
+	coded.SetSplitPolicy(OFDSP_Duplicate)
+	coded.SetMergePolicy(OFDMP_Sum)
+	if coded.SplitPolicy() != OFDSP_Duplicate || coded.MergePolicy() != OFDMP_Sum {
+		t.Error("split or merge policy not set")
+	}
+}
+
+func TestFieldDomainValidate(t *testing.T) {
+	ds, layer := createMemoryVectorLayer(t, "domains")
+	defer ds.Destroy()
+	addLayerField(t, layer, "landuse", FT_String)
+	addLayerField(t, layer, "percent", FT_Real)
+	addLayerField(t, layer, "code", FT_String)
+
+	coded, _ := CreateCodedFieldDomain("landuse", "", FT_String, FST_None, []CodedValue{{"F", "Forest"}})
+	defer coded.Destroy()
+	ranged, _ := CreateRangeFieldDomain("percent", "", FT_Real, FST_None,
+		RangeBound{Value: 0.0, Inclusive: true}, RangeBound{Value: 100.0})
+	defer ranged.Destroy()
+	glob, _ := CreateGlobFieldDomain("code", "", FT_String, FST_None, "[A-Z]??-*")
+	defer glob.Destroy()
+
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+
+	if err := coded.Validate(feature, 0); err != nil {
+		t.Errorf("unset value should be valid: %v", err)
+	}
+	for _, test := range []struct {
+		domain FieldDomain
+		index  int
+		set    func()
+		valid  bool
+	}{
+		{coded, 0, func() { feature.SetFieldString(0, "F") }, true},
+		{coded, 0, func() { feature.SetFieldString(0, "X") }, false},
+		{ranged, 1, func() { feature.SetFieldFloat64(1, 0) }, true},
+		{ranged, 1, func() { feature.SetFieldFloat64(1, 99.5) }, true},
+		{ranged, 1, func() { feature.SetFieldFloat64(1, 100) }, false},
+		{ranged, 1, func() { feature.SetFieldFloat64(1, -1) }, false},
+		{glob, 2, func() { feature.SetFieldString(2, "Abc-123") }, true},
+		{glob, 2, func() { feature.SetFieldString(2, "abc-123") }, false},
+		{glob, 2, func() { feature.SetFieldString(2, "Ab-123") }, false},
+		{glob, 2, func() { feature.SetFieldNull(2) }, true},
+	} {
+		test.set()
+		err := test.domain.Validate(feature, test.index)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: Validate(%q) = %v, want valid %v",
+				test.domain.Name(), feature.FieldAsString(test.index), err, test.valid)
+		}
+	}
+}
+
+func TestDatasetFieldDomains(t *testing.T) {
+	filename := "./tmp/fielddomain.gpkg"
+	os.Remove(filename)
+	defer os.Remove(filename)
+
+	driver, err := GetDriverByName(DriverNameGPKG)
+	if err != nil {
+		t.Fatalf("GetDriverByName(GPKG): %v", err)
+	}
+	ds := driver.Create(filename, 0, 0, 0, Unknown, nil)
+	if ds.cval == nil {
+		t.Fatal("GPKG Create returned nil dataset")
+	}
+	defer ds.Close()
+
+	coded, _ := CreateCodedFieldDomain("landuse", "Land use", FT_String, FST_None,
+		[]CodedValue{{"F", "Forest"}, {"W", "Water"}})
+	defer coded.Destroy()
+	if err := ds.AddFieldDomain(coded); err != nil {
+		t.Fatalf("AddFieldDomain: %v", err)
+	}
+	if err := ds.AddFieldDomain(coded); err == nil {
+		t.Error("adding a domain twice succeeded")
+	}
+
+	layer, err := ds.CreateLayer("parcels", SpatialReference{}, GT_Point, nil)
+	if err != nil {
+		t.Fatalf("CreateLayer: %v", err)
+	}
+	fd := CreateFieldDefinition("landuse", FT_String)
+	fd.SetDomainName("landuse")
+	if err := layer.CreateField(fd, false); err != nil {
+		t.Fatalf("CreateField: %v", err)
+	}
+	fd.Destroy()
+
+	definition := layer.Definition()
+	if name := definition.FieldDefinition(0).DomainName(); name != "landuse" {
+		t.Errorf("DomainName = %q, want landuse", name)
+	}
+
+	domain, ok := ds.FieldDomain("landuse")
+	if !ok || domain.Description() != "Land use" || len(domain.CodedValues()) != 2 {
+		t.Fatalf("FieldDomain(landuse) = %v, %v", domain.CodedValues(), ok)
+	}
+	if _, ok := ds.FieldDomain("missing"); ok {
+		t.Error("FieldDomain(missing) found a domain")
+	}
+
+	feature := definition.Create()
+	defer feature.Destroy()
+	feature.SetFieldString(0, "W")
+	if err := ds.ValidateFieldDomains(feature); err != nil {
+		t.Errorf("ValidateFieldDomains(W): %v", err)
+	}
+	feature.SetFieldString(0, "Q")
+	if err := ds.ValidateFieldDomains(feature); err == nil {
+		t.Error("ValidateFieldDomains(Q) succeeded")
+	}
+
+	names, err := ds.FieldDomainNames()
+	if VERSION_NUM < 3050000 {
+		if err == nil {
+			t.Error("FieldDomainNames succeeded before GDAL 3.5")
+		}
+		return
+	}
+	if err != nil || !reflect.DeepEqual(names, []string{"landuse"}) {
+		t.Errorf("FieldDomainNames = %v, %v", names, err)
+	}
+
+	updated, _ := CreateCodedFieldDomain("landuse", "Land use", FT_String, FST_None,
+		[]CodedValue{{"F", "Forest"}, {"W", "Water"}, {"Q", "Quarry"}})
+	defer updated.Destroy()
+	if err := ds.UpdateFieldDomain(updated); err != nil {
+		t.Fatalf("UpdateFieldDomain: %v", err)
+	}
+	if err := ds.ValidateFieldDomains(feature); err != nil {
+		t.Errorf("ValidateFieldDomains(Q) after update: %v", err)
+	}
+
+	if err := ds.DeleteFieldDomain("landuse"); err != nil {
+		t.Fatalf("DeleteFieldDomain: %v", err)
+	}
+	if _, ok := ds.FieldDomain("landuse"); ok {
+		t.Error("domain still present after DeleteFieldDomain")
+	}
+}
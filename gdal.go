@@ -6,6 +6,7 @@ package gdal
 */
 import "C"
 import (
+	"context"
 	"errors"
 	"fmt"
 	"runtime/cgo"
@@ -433,6 +434,15 @@ func (dataType DataType) Name() string {
 	return C.GoString(C.GDALGetDataTypeName(C.GDALDataType(dataType)))
 }
 
+// DataTypeByName returns the data type with the given name, e.g. "Float32",
+// or Unknown.
+func DataTypeByName(name string) DataType {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	return DataType(C.GDALGetDataTypeByName(cName))
+}
+
 // Union wraps the corresponding GDAL/OGR operation.
 func (dataType DataType) Union(dataTypeB DataType) DataType {
 	return DataType(
@@ -788,10 +798,20 @@ const (
 	DMD_EXTENSION          = string(C.GDAL_DMD_EXTENSION)
 	DMD_CREATIONOPTIONLIST = string(C.GDAL_DMD_CREATIONOPTIONLIST)
 	DMD_CREATIONDATATYPES  = string(C.GDAL_DMD_CREATIONDATATYPES)
+	DMD_OPENOPTIONLIST     = string(C.GDAL_DMD_OPENOPTIONLIST)
+	DMD_EXTENSIONS         = string(C.GDAL_DMD_EXTENSIONS)
+	DMD_SUBDATASETS        = string(C.GDAL_DMD_SUBDATASETS)
+
+	DS_LAYER_CREATIONOPTIONLIST = string(C.GDAL_DS_LAYER_CREATIONOPTIONLIST)
 
 	DCAP_CREATE     = string(C.GDAL_DCAP_CREATE)
 	DCAP_CREATECOPY = string(C.GDAL_DCAP_CREATECOPY)
 	DCAP_VIRTUALIO  = string(C.GDAL_DCAP_VIRTUALIO)
+	DCAP_OPEN       = string(C.GDAL_DCAP_OPEN)
+	DCAP_RASTER     = string(C.GDAL_DCAP_RASTER)
+	DCAP_VECTOR     = string(C.GDAL_DCAP_VECTOR)
+
+	DCAP_MULTIDIM_RASTER = string(C.GDAL_DCAP_MULTIDIM_RASTER)
 )
 
 // Create a new dataset with this driver.
@@ -1220,6 +1240,11 @@ func (dataset Dataset) FileList() []string {
 	return cStringListToSlice(C.GDALGetFileList(dataset.cval))
 }
 
+// Description returns the dataset description, usually its file name.
+func (dataset Dataset) Description() string {
+	return goString(C.GDALGetDescription(C.GDALMajorObjectH(unsafe.Pointer(dataset.cval))))
+}
+
 // Close the dataset
 func (dataset Dataset) Close() {
 	C.GDALClose(dataset.cval)
@@ -1559,6 +1584,20 @@ func (dataset Dataset) BuildOverviews(
 	))
 }
 
+// ClearOverviews removes all overviews of the dataset.
+func (dataset Dataset) ClearOverviews() error {
+	cResampling := C.CString("NONE")
+	defer C.free(unsafe.Pointer(cResampling))
+
+	return ErrFromCPLErr(C.GDALBuildOverviews(
+		dataset.cval,
+		cResampling,
+		0, nil,
+		0, nil,
+		nil, nil,
+	))
+}
+
 // Unimplemented: GDALGetOpenDatasets
 
 // Access returns access flag.
@@ -1572,8 +1611,12 @@ func (dataset Dataset) FlushCache() {
 	C.GDALFlushCache(dataset.cval)
 }
 
-// CreateMaskBand wraps the corresponding GDAL/OGR operation.
-func (dataset Dataset) CreateMaskBand(flags int) error {
+// CreateMaskBand creates a mask band shared by all bands of the dataset.
+// Zero flags select GMF_PER_DATASET for GTiff, the only value it supports.
+func (dataset Dataset) CreateMaskBand(flags MaskFlags) error {
+	if flags == 0 && dataset.Driver().ShortName() == DriverNameGTiff {
+		flags = GMF_PER_DATASET
+	}
 	return ErrFromCPLErr(C.GDALCreateDatasetMaskBand(dataset.cval, C.int(flags)))
 }
 
@@ -1791,9 +1834,11 @@ func (rasterBand RasterBand) GetMaximum() (val float64, valid bool) {
 	return float64(max), success != 0
 }
 
-// GetStatistics returns image statistics.
-func (rasterBand RasterBand) GetStatistics(approxOK, force int) (min, max, mean, stdDev float64) {
-	C.GDALGetRasterStatistics(
+// GetStatistics returns image statistics. With force set to 0 and no cached
+// statistics, ErrWarning is returned.
+func (rasterBand RasterBand) GetStatistics(approxOK, force int) (Statistics, error) {
+	var min, max, mean, stdDev float64
+	err := ErrFromCPLErr(C.GDALGetRasterStatistics(
 		rasterBand.cval,
 		C.int(approxOK),
 		C.int(force),
@@ -1801,20 +1846,26 @@ func (rasterBand RasterBand) GetStatistics(approxOK, force int) (min, max, mean,
 		(*C.double)(unsafe.Pointer(&max)),
 		(*C.double)(unsafe.Pointer(&mean)),
 		(*C.double)(unsafe.Pointer(&stdDev)),
-	)
-	return min, max, mean, stdDev
+	))
+	if err != nil {
+		return Statistics{}, err
+	}
+	return rasterBand.newStatistics(min, max, mean, stdDev), nil
 }
 
-// ComputeStatistics computes image statistics.
+// ComputeStatistics computes image statistics. The computation is
+// interrupted when ctx is done.
 func (rasterBand RasterBand) ComputeStatistics(
+	ctx context.Context,
 	approxOK int,
 	progress ProgressFunc,
 	data interface{},
-) (min, max, mean, stdDev float64) {
-	callback := newGoGDALProgressCallback(progress, data)
+) (Statistics, error) {
+	callback := newGoGDALProgressCallback(contextProgress(ctx, progress), data)
 	defer callback.close()
 
-	C.GDALComputeRasterStatistics(
+	var min, max, mean, stdDev float64
+	err := ErrFromCPLErr(C.GDALComputeRasterStatistics(
 		rasterBand.cval,
 		C.int(approxOK),
 		(*C.double)(unsafe.Pointer(&min)),
@@ -1823,8 +1874,14 @@ func (rasterBand RasterBand) ComputeStatistics(
 		(*C.double)(unsafe.Pointer(&stdDev)),
 		callback.fn,
 		callback.arg,
-	)
-	return min, max, mean, stdDev
+	))
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return Statistics{}, ctxErr
+	}
+	if err != nil {
+		return Statistics{}, err
+	}
+	return rasterBand.newStatistics(min, max, mean, stdDev), nil
 }
 
 // SetStatistics sets statistics on raster band.
@@ -1958,8 +2015,12 @@ func (rasterBand RasterBand) DefaultHistogram(
 
 // Unimplemented: GetRandomRasterSample
 
-// Fetch best sampling overviews
-// Unimplemented: GetRasterSampleOverview
+// SampleOverview returns the most reduced overview that still has at least
+// desiredSamples pixels, or the band itself if there is none.
+func (rasterBand RasterBand) SampleOverview(desiredSamples int) RasterBand {
+	overview := C.GDALGetRasterSampleOverviewEx(rasterBand.cval, C.GUIntBig(desiredSamples))
+	return RasterBand{overview}
+}
 
 // Fill this band with a constant value
 func (rasterBand RasterBand) Fill(real, imaginary float64) error {
@@ -1981,8 +2042,6 @@ func (rasterBand RasterBand) SetDefaultRAT(rat RasterAttributeTable) error {
 	return ErrFromCPLErr(C.GDALSetDefaultRAT(rasterBand.cval, rat.cval))
 }
 
-// Unimplemented: AddDerivedBandPixelFunc
-
 // GetMaskBand returns the mask band associated with the band.
 func (rasterBand RasterBand) GetMaskBand() RasterBand {
 	mask := C.GDALGetMaskBand(rasterBand.cval)
@@ -1990,13 +2049,13 @@ func (rasterBand RasterBand) GetMaskBand() RasterBand {
 }
 
 // GetMaskFlags returns the status flags of the mask band associated with the band.
-func (rasterBand RasterBand) GetMaskFlags() int {
+func (rasterBand RasterBand) GetMaskFlags() MaskFlags {
 	flags := C.GDALGetMaskFlags(rasterBand.cval)
-	return int(flags)
+	return MaskFlags(flags)
 }
 
 // CreateMaskBand wraps the corresponding GDAL/OGR operation.
-func (rasterBand RasterBand) CreateMaskBand(flags int) error {
+func (rasterBand RasterBand) CreateMaskBand(flags MaskFlags) error {
 	return ErrFromCPLErr(C.GDALCreateMaskBand(rasterBand.cval, C.int(flags)))
 }
 
@@ -2027,8 +2086,38 @@ func (rasterBand RasterBand) RasterBandCopyWholeRaster(
 	))
 }
 
-// Generate downsampled overviews
-// Unimplemented: RegenerateOverviews
+// RegenerateOverviews generates downsampled overviews of this band into
+// existing overview bands using the given resampling method.
+func (rasterBand RasterBand) RegenerateOverviews(
+	overviews []RasterBand,
+	resampling string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	if len(overviews) == 0 {
+		return fmt.Errorf("error: overviews must not be empty")
+	}
+
+	cResampling := C.CString(resampling)
+	defer C.free(unsafe.Pointer(cResampling))
+
+	cOverviews := make([]C.GDALRasterBandH, len(overviews))
+	for i, overview := range overviews {
+		cOverviews[i] = overview.cval
+	}
+
+	callback := newGoGDALProgressCallback(progress, data)
+	defer callback.close()
+
+	return ErrFromCPLErr(C.GDALRegenerateOverviews(
+		rasterBand.cval,
+		C.int(len(cOverviews)),
+		cRasterBandHandleSlicePtr(cOverviews),
+		cResampling,
+		callback.fn,
+		callback.arg,
+	))
+}
 
 /* ==================================================================== */
 /*     GDALAsyncReader                                                  */
@@ -2332,3 +2421,47 @@ func VSIFReadL(nSize, nCount int, file VSILFILE) []byte {
 
 	return data
 }
+
+// VSIFileFromMemBuffer creates the in-memory file filename holding a copy of data.
+func VSIFileFromMemBuffer(filename string, data []byte) error {
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+
+	buffer := C.CBytes(data)
+	file := C.VSIFileFromMemBuffer(cFilename, (*C.GByte)(buffer), C.vsi_l_offset(len(data)), 1)
+	if file == nil {
+		C.free(buffer)
+		return fmt.Errorf("error: failed to create in-memory file %q", filename)
+	}
+	C.VSIFCloseL(file)
+	return nil
+}
+
+// VSIGetMemFileBuffer returns a copy of the content of the in-memory file
+// filename. The file is removed afterwards when unlink is true.
+func VSIGetMemFileBuffer(filename string, unlink bool) ([]byte, error) {
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+
+	var length C.vsi_l_offset
+	buffer := C.VSIGetMemFileBuffer(cFilename, &length, 0)
+	if buffer == nil {
+		return nil, fmt.Errorf("error: in-memory file %q not found", filename)
+	}
+	data := C.GoBytes(unsafe.Pointer(buffer), C.int(length))
+	if unlink {
+		C.VSIUnlink(cFilename)
+	}
+	return data, nil
+}
+
+// VSIUnlink deletes the file filename.
+func VSIUnlink(filename string) error {
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+
+	if C.VSIUnlink(cFilename) != 0 {
+		return fmt.Errorf("error: failed to unlink %q", filename)
+	}
+	return nil
+}
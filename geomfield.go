@@ -0,0 +1,195 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_ogr_geomfield.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// GeomFieldDefinition describes a geometry field of a layer.
+type GeomFieldDefinition struct {
+	cval C.OGRGeomFieldDefnH
+}
+
+// CoordinatePrecision holds the resolutions at which coordinates of a
+// geometry field are stored, 0 meaning unknown.
+type CoordinatePrecision struct {
+	XYResolution float64
+	ZResolution  float64
+	MResolution  float64
+}
+
+// CreateGeomFieldDefinition creates a new geometry field definition.
+func CreateGeomFieldDefinition(name string, geomType GeometryType) GeomFieldDefinition {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return GeomFieldDefinition{C.OGR_GFld_Create(cName, C.OGRwkbGeometryType(geomType))}
+}
+
+// Destroy the geometry field definition.
+func (gfd GeomFieldDefinition) Destroy() {
+	C.OGR_GFld_Destroy(gfd.cval)
+}
+
+// Name returns the name of the geometry field.
+func (gfd GeomFieldDefinition) Name() string {
+	return C.GoString(C.OGR_GFld_GetNameRef(gfd.cval))
+}
+
+// SetName sets the name of the geometry field.
+func (gfd GeomFieldDefinition) SetName(name string) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.OGR_GFld_SetName(gfd.cval, cName)
+}
+
+// Type returns the geometry type of the field.
+func (gfd GeomFieldDefinition) Type() GeometryType {
+	return GeometryType(C.OGR_GFld_GetType(gfd.cval))
+}
+
+// SetType sets the geometry type of the field.
+func (gfd GeomFieldDefinition) SetType(geomType GeometryType) {
+	C.OGR_GFld_SetType(gfd.cval, C.OGRwkbGeometryType(geomType))
+}
+
+// SpatialReference returns the spatial reference of the field, owned by the
+// definition. It is null when the field has none.
+func (gfd GeomFieldDefinition) SpatialReference() SpatialReference {
+	return SpatialReference{C.OGR_GFld_GetSpatialRef(gfd.cval)}
+}
+
+// SetSpatialReference sets the spatial reference of the field. The
+// definition keeps its own reference.
+func (gfd GeomFieldDefinition) SetSpatialReference(sr SpatialReference) {
+	C.OGR_GFld_SetSpatialRef(gfd.cval, sr.cval)
+}
+
+// IsNullable returns whether the field accepts null geometries.
+func (gfd GeomFieldDefinition) IsNullable() bool {
+	return C.OGR_GFld_IsNullable(gfd.cval) != 0
+}
+
+// SetNullable sets whether the field accepts null geometries.
+func (gfd GeomFieldDefinition) SetNullable(nullable bool) {
+	C.OGR_GFld_SetNullable(gfd.cval, BoolToCInt(nullable))
+}
+
+// IsIgnored returns whether the field is ignored when fetching features.
+func (gfd GeomFieldDefinition) IsIgnored() bool {
+	return C.OGR_GFld_IsIgnored(gfd.cval) != 0
+}
+
+// SetIgnored sets whether the field is ignored when fetching features.
+func (gfd GeomFieldDefinition) SetIgnored(ignore bool) {
+	C.OGR_GFld_SetIgnored(gfd.cval, BoolToCInt(ignore))
+}
+
+// CoordinatePrecision returns the coordinate precision of the field.
+// Requires GDAL 3.9 or newer.
+func (gfd GeomFieldDefinition) CoordinatePrecision() (CoordinatePrecision, error) {
+	var xy, z, m C.double
+	if C.go_OGR_GFld_GetCoordinatePrecision(gfd.cval, &xy, &z, &m) == -1 {
+		return CoordinatePrecision{}, fmt.Errorf("error: coordinate precision requires GDAL 3.9 or newer")
+	}
+	return CoordinatePrecision{float64(xy), float64(z), float64(m)}, nil
+}
+
+// SetCoordinatePrecision sets the coordinate precision of the field, used
+// by drivers to round coordinates on write. Requires GDAL 3.9 or newer.
+func (gfd GeomFieldDefinition) SetCoordinatePrecision(precision CoordinatePrecision) error {
+	if C.go_OGR_GFld_SetCoordinatePrecision(
+		gfd.cval,
+		C.double(precision.XYResolution),
+		C.double(precision.ZResolution),
+		C.double(precision.MResolution),
+	) == -1 {
+		return fmt.Errorf("error: coordinate precision requires GDAL 3.9 or newer")
+	}
+	return nil
+}
+
+// GeomFieldCount returns the number of geometry fields.
+func (fd FeatureDefinition) GeomFieldCount() int {
+	return int(C.OGR_FD_GetGeomFieldCount(fd.cval))
+}
+
+// GeomFieldDefinition returns the geometry field definition at index.
+func (fd FeatureDefinition) GeomFieldDefinition(index int) GeomFieldDefinition {
+	return GeomFieldDefinition{C.OGR_FD_GetGeomFieldDefn(fd.cval, C.int(index))}
+}
+
+// GeomFieldIndex returns the index of the named geometry field, or -1.
+func (fd FeatureDefinition) GeomFieldIndex(name string) int {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return int(C.OGR_FD_GetGeomFieldIndex(fd.cval, cName))
+}
+
+// AddGeomFieldDefinition adds a copy of the geometry field definition.
+func (fd FeatureDefinition) AddGeomFieldDefinition(gfd GeomFieldDefinition) {
+	C.OGR_FD_AddGeomFieldDefn(fd.cval, gfd.cval)
+}
+
+// DeleteGeomFieldDefinition deletes the geometry field definition at index.
+func (fd FeatureDefinition) DeleteGeomFieldDefinition(index int) error {
+	return ErrFromOGRErr(C.OGR_FD_DeleteGeomFieldDefn(fd.cval, C.int(index)))
+}
+
+// CreateGeomField creates a new geometry field on a layer.
+func (layer Layer) CreateGeomField(gfd GeomFieldDefinition, approxOK bool) error {
+	return ErrFromOGRErr(C.OGR_L_CreateGeomField(layer.cval, gfd.cval, BoolToCInt(approxOK)))
+}
+
+// SetSpatialFilterEx sets a spatial filter on the geometry field at index.
+func (layer Layer) SetSpatialFilterEx(geomField int, filter Geometry) {
+	C.OGR_L_SetSpatialFilterEx(layer.cval, C.int(geomField), filter.cval)
+}
+
+// SetSpatialFilterRectEx sets a rectangular spatial filter on the geometry
+// field at index.
+func (layer Layer) SetSpatialFilterRectEx(geomField int, minX, minY, maxX, maxY float64) {
+	C.OGR_L_SetSpatialFilterRectEx(
+		layer.cval,
+		C.int(geomField),
+		C.double(minX), C.double(minY), C.double(maxX), C.double(maxY),
+	)
+}
+
+// GeomFieldCount returns the number of geometry fields of the feature.
+func (feature Feature) GeomFieldCount() int {
+	return int(C.OGR_F_GetGeomFieldCount(feature.cval))
+}
+
+// GeomFieldDefinition returns the definition of the geometry field at index.
+func (feature Feature) GeomFieldDefinition(index int) GeomFieldDefinition {
+	return GeomFieldDefinition{C.OGR_F_GetGeomFieldDefnRef(feature.cval, C.int(index))}
+}
+
+// GeomFieldIndex returns the index of the named geometry field, or -1.
+func (feature Feature) GeomFieldIndex(name string) int {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return int(C.OGR_F_GetGeomFieldIndex(feature.cval, cName))
+}
+
+// GeomField returns the geometry of the field at index, owned by the
+// feature.
+func (feature Feature) GeomField(index int) Geometry {
+	return Geometry{C.OGR_F_GetGeomFieldRef(feature.cval, C.int(index))}
+}
+
+// SetGeomField sets the geometry of the field at index to a copy of geom.
+func (feature Feature) SetGeomField(index int, geom Geometry) error {
+	return ErrFromOGRErr(C.OGR_F_SetGeomField(feature.cval, C.int(index), geom.cval))
+}
+
+// SetGeomFieldDirectly sets the geometry of the field at index, passing
+// ownership of geom to the feature.
+func (feature Feature) SetGeomFieldDirectly(index int, geom Geometry) error {
+	return ErrFromOGRErr(C.OGR_F_SetGeomFieldDirectly(feature.cval, C.int(index), geom.cval))
+}
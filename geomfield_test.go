@@ -0,0 +1,122 @@
+package gdal
+
+import "testing"
+
+func TestLayerMultipleGeomFields(t *testing.T) {
+	ds, ok := OGRDriverByName(OGRDriverNameMemory).Create("multigeom", nil)
+	if !ok {
+		t.Fatal("failed to create OGR memory datasource")
+	}
+	defer ds.Destroy()
+	layer := ds.CreateLayer("multigeom", SpatialReference{}, GT_None, nil)
+	if layer.cval == nil {
+		t.Fatal("failed to create OGR memory layer")
+	}
+
+	sr := createSpatialReferenceFromEPSG(t, 4326)
+	defer sr.Destroy()
+
+	footprint := CreateGeomFieldDefinition("footprint", GT_Polygon)
+	footprint.SetSpatialReference(sr)
+	centroid := CreateGeomFieldDefinition("centroid", GT_Point)
+	centroid.SetNullable(false)
+	for _, gfd := range []GeomFieldDefinition{footprint, centroid} {
+		if err := layer.CreateGeomField(gfd, false); err != nil {
+			t.Fatalf("CreateGeomField(%s): %v", gfd.Name(), err)
+		}
+		gfd.Destroy()
+	}
+
+	definition := layer.Definition()
+	if count := definition.GeomFieldCount(); count != 2 {
+		t.Fatalf("GeomFieldCount = %d, want 2", count)
+	}
+	index := definition.GeomFieldIndex("centroid")
+	if index != 1 {
+		t.Fatalf("GeomFieldIndex(centroid) = %d, want 1", index)
+	}
+	gfd := definition.GeomFieldDefinition(index)
+	if gfd.Type() != GT_Point || gfd.IsNullable() {
+		t.Errorf("centroid: type %v nullable %v", gfd.Type(), gfd.IsNullable())
+	}
+	if !definition.GeomFieldDefinition(0).SpatialReference().IsSame(sr) {
+		t.Error("footprint lost its spatial reference")
+	}
+
+	for i := 0; i < 3; i++ {
+		feature := definition.Create()
+		polygon, err := CreateFromWKT("POLYGON ((0 0,1 0,1 1,0 1,0 0))", SpatialReference{})
+		if err != nil {
+			t.Fatalf("CreateFromWKT: %v", err)
+		}
+		if err := feature.SetGeomFieldDirectly(0, polygon); err != nil {
+			t.Fatalf("SetGeomFieldDirectly: %v", err)
+		}
+		point, err := CreateFromWKT("POINT (10 10)", SpatialReference{})
+		if err != nil {
+			t.Fatalf("CreateFromWKT: %v", err)
+		}
+		if i == 2 {
+			point.Destroy()
+			point, _ = CreateFromWKT("POINT (50 50)", SpatialReference{})
+		}
+		if err := feature.SetGeomField(feature.GeomFieldIndex("centroid"), point); err != nil {
+			t.Fatalf("SetGeomField: %v", err)
+		}
+		point.Destroy()
+		if err := layer.Create(feature); err != nil {
+			t.Fatalf("Layer.Create: %v", err)
+		}
+		feature.Destroy()
+	}
+
+	layer.SetSpatialFilterRectEx(1, 40, 40, 60, 60)
+	var matched int
+	for feature, err := range layer.Features() {
+		if err != nil {
+			t.Fatalf("Features: %v", err)
+		}
+		matched++
+		if feature.GeomFieldCount() != 2 {
+			t.Errorf("feature GeomFieldCount = %d, want 2", feature.GeomFieldCount())
+		}
+		wkt, err := feature.GeomField(1).ToWKT()
+		if err != nil || wkt != "POINT (50 50)" {
+			t.Errorf("centroid = %q, %v", wkt, err)
+		}
+		if feature.GeomField(0).IsNull() {
+			t.Error("footprint is null")
+		}
+	}
+	if matched != 1 {
+		t.Errorf("spatial filter on centroid matched %d features, want 1", matched)
+	}
+
+	// The footprints all cover the same area, so filtering them keeps all
+	// features.
+	layer.SetSpatialFilterRectEx(0, 0.5, 0.5, 0.6, 0.6)
+	if count, _ := layer.FeatureCount(true); count != 3 {
+		t.Errorf("spatial filter on footprint matched %d features, want 3", count)
+	}
+}
+
+func TestGeomFieldCoordinatePrecision(t *testing.T) {
+	gfd := CreateGeomFieldDefinition("geom", GT_Point)
+	defer gfd.Destroy()
+
+	want := CoordinatePrecision{XYResolution: 1e-7, ZResolution: 1e-3}
+	err := gfd.SetCoordinatePrecision(want)
+	if VERSION_NUM < 3090000 {
+		if err == nil {
+			t.Error("SetCoordinatePrecision succeeded before GDAL 3.9")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("SetCoordinatePrecision: %v", err)
+	}
+	got, err := gfd.CoordinatePrecision()
+	if err != nil || got != want {
+		t.Errorf("CoordinatePrecision = %+v, %v, want %+v", got, err, want)
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/mtfelian/gdal/v2
 
-go 1.17
+go 1.23
@@ -0,0 +1,23 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_gdal_options.h"
+
+#include <cpl_error.h>
+#include <cpl_string.h>
+
+static void CPL_STDCALL go_CollectErrorHandler(CPLErr eErrClass, CPLErrorNum nError, const char *pszMessage) {
+    if (eErrClass < CE_Warning) {
+        return;
+    }
+    char ***ppapszMessages = (char ***)CPLGetErrorHandlerUserData();
+    *ppapszMessages = CSLAddString(*ppapszMessages, pszMessage);
+}
+
+int go_ValidateCreationOptions(GDALDriverH hDriver, char **papszOptions, char ***ppapszMessages) {
+    *ppapszMessages = NULL;
+    CPLPushErrorHandlerEx(go_CollectErrorHandler, ppapszMessages);
+    int bValid = GDALValidateCreationOptions(hDriver, (CSLConstList)papszOptions);
+    CPLPopErrorHandler();
+    return bValid;
+}
@@ -0,0 +1,14 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_GDAL_OPTIONS_H_
+#define GO_GDAL_OPTIONS_H_
+
+#include <gdal.h>
+
+// go_ValidateCreationOptions validates papszOptions against the driver and
+// collects the messages GDAL reports into *ppapszMessages, to be freed with
+// CSLDestroy.
+int go_ValidateCreationOptions(GDALDriverH hDriver, char **papszOptions, char ***ppapszMessages);
+
+#endif  // GO_GDAL_OPTIONS_H_
@@ -0,0 +1,49 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_gdal_pixfunc.h"
+#include "_cgo_export.h"
+
+#include <stdlib.h>
+#include <cpl_error.h>
+#include <cpl_string.h>
+#include <gdal_version.h>
+
+#define GO_PIXEL_FUNC_HANDLE "go_pixel_func_handle"
+
+void go_PixelFuncError(const char *pszMessage) {
+    CPLError(CE_Failure, CPLE_AppDefined, "%s", pszMessage);
+}
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 4, 0)
+
+static CPLErr go_PixelFuncProxy(
+    void **papoSources, int nSources, void *pData,
+    int nBufXSize, int nBufYSize,
+    GDALDataType eSrcType, GDALDataType eBufType,
+    int nPixelSpace, int nLineSpace,
+    CSLConstList papszFunctionArgs
+) {
+    const char *pszHandle = CSLFetchNameValue((char **)papszFunctionArgs, GO_PIXEL_FUNC_HANDLE);
+    if (pszHandle == NULL) {
+        go_PixelFuncError("Go pixel function handle is missing");
+        return CE_Failure;
+    }
+    uintptr_t handle = (uintptr_t)strtoull(pszHandle, NULL, 10);
+    return (CPLErr)goGDALPixelFuncProxyA(
+        handle, papoSources, nSources, pData,
+        nBufXSize, nBufYSize, (int)eSrcType, (int)eBufType,
+        nPixelSpace, nLineSpace, (char **)papszFunctionArgs);
+}
+
+int go_AddPixelFunc(const char *pszName, const char *pszMetadata) {
+    return (int)GDALAddDerivedBandPixelFuncWithArgs(pszName, go_PixelFuncProxy, pszMetadata);
+}
+
+#else
+
+int go_AddPixelFunc(const char *pszName, const char *pszMetadata) {
+    return -1;
+}
+
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 4, 0)
@@ -0,0 +1,17 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_GDAL_PIXFUNC_H_
+#define GO_GDAL_PIXFUNC_H_
+
+#include <stdint.h>
+#include <gdal.h>
+
+// go_AddPixelFunc registers the Go pixel function trampoline under pszName.
+// It returns -1 when the GDAL version lacks GDALAddDerivedBandPixelFuncWithArgs.
+int go_AddPixelFunc(const char *pszName, const char *pszMetadata);
+
+// go_PixelFuncError reports a pixel function failure through CPLError.
+void go_PixelFuncError(const char *pszMessage);
+
+#endif  // GO_GDAL_PIXFUNC_H_
@@ -0,0 +1,114 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_gdal_relationship.h"
+
+#include <gdal_version.h>
+#include <cpl_conv.h>
+#include <cpl_string.h>
+
+void go_FreeRelationship(goRelationship *psRel) {
+    CPLFree(psRel->pszName);
+    CPLFree(psRel->pszLeftTableName);
+    CPLFree(psRel->pszRightTableName);
+    CPLFree(psRel->pszMappingTableName);
+    CSLDestroy(psRel->papszLeftTableFields);
+    CSLDestroy(psRel->papszRightTableFields);
+    CSLDestroy(psRel->papszLeftMappingTableFields);
+    CSLDestroy(psRel->papszRightMappingTableFields);
+    CPLFree(psRel->pszForwardPathLabel);
+    CPLFree(psRel->pszBackwardPathLabel);
+    CPLFree(psRel->pszRelatedTableType);
+}
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 6, 0)
+
+int go_GDALDatasetGetRelationshipNames(GDALDatasetH hDS, char ***ppapszNames) {
+    *ppapszNames = GDALDatasetGetRelationshipNames(hDS, NULL);
+    return 0;
+}
+
+int go_GDALDatasetGetRelationship(GDALDatasetH hDS, const char *pszName, goRelationship *psRel) {
+    GDALRelationshipH hRel = GDALDatasetGetRelationship(hDS, pszName);
+    if (hRel == NULL) {
+        return 0;
+    }
+    psRel->pszName = CPLStrdup(GDALRelationshipGetName(hRel));
+    psRel->pszLeftTableName = CPLStrdup(GDALRelationshipGetLeftTableName(hRel));
+    psRel->pszRightTableName = CPLStrdup(GDALRelationshipGetRightTableName(hRel));
+    psRel->pszMappingTableName = CPLStrdup(GDALRelationshipGetMappingTableName(hRel));
+    psRel->nCardinality = (int)GDALRelationshipGetCardinality(hRel);
+    psRel->nType = (int)GDALRelationshipGetType(hRel);
+    psRel->papszLeftTableFields = GDALRelationshipGetLeftTableFields(hRel);
+    psRel->papszRightTableFields = GDALRelationshipGetRightTableFields(hRel);
+    psRel->papszLeftMappingTableFields = GDALRelationshipGetLeftMappingTableFields(hRel);
+    psRel->papszRightMappingTableFields = GDALRelationshipGetRightMappingTableFields(hRel);
+    psRel->pszForwardPathLabel = CPLStrdup(GDALRelationshipGetForwardPathLabel(hRel));
+    psRel->pszBackwardPathLabel = CPLStrdup(GDALRelationshipGetBackwardPathLabel(hRel));
+    psRel->pszRelatedTableType = CPLStrdup(GDALRelationshipGetRelatedTableType(hRel));
+    return 1;
+}
+
+static GDALRelationshipH go_CreateRelationship(const goRelationship *psRel) {
+    GDALRelationshipH hRel =
+        GDALRelationshipCreate(psRel->pszName, psRel->pszLeftTableName, psRel->pszRightTableName,
+                               (GDALRelationshipCardinality)psRel->nCardinality);
+    GDALRelationshipSetMappingTableName(hRel, psRel->pszMappingTableName);
+    GDALRelationshipSetType(hRel, (GDALRelationshipType)psRel->nType);
+    GDALRelationshipSetLeftTableFields(hRel, psRel->papszLeftTableFields);
+    GDALRelationshipSetRightTableFields(hRel, psRel->papszRightTableFields);
+    GDALRelationshipSetLeftMappingTableFields(hRel, psRel->papszLeftMappingTableFields);
+    GDALRelationshipSetRightMappingTableFields(hRel, psRel->papszRightMappingTableFields);
+    GDALRelationshipSetForwardPathLabel(hRel, psRel->pszForwardPathLabel);
+    GDALRelationshipSetBackwardPathLabel(hRel, psRel->pszBackwardPathLabel);
+    GDALRelationshipSetRelatedTableType(hRel, psRel->pszRelatedTableType);
+    return hRel;
+}
+
+int go_GDALDatasetAddRelationship(GDALDatasetH hDS, const goRelationship *psRel,
+                                  char **ppszFailureReason) {
+    GDALRelationshipH hRel = go_CreateRelationship(psRel);
+    int bOK = GDALDatasetAddRelationship(hDS, hRel, ppszFailureReason);
+    GDALDestroyRelationship(hRel);
+    return bOK ? 1 : 0;
+}
+
+int go_GDALDatasetUpdateRelationship(GDALDatasetH hDS, const goRelationship *psRel,
+                                     char **ppszFailureReason) {
+    GDALRelationshipH hRel = go_CreateRelationship(psRel);
+    int bOK = GDALDatasetUpdateRelationship(hDS, hRel, ppszFailureReason);
+    GDALDestroyRelationship(hRel);
+    return bOK ? 1 : 0;
+}
+
+int go_GDALDatasetDeleteRelationship(GDALDatasetH hDS, const char *pszName,
+                                     char **ppszFailureReason) {
+    return GDALDatasetDeleteRelationship(hDS, pszName, ppszFailureReason) ? 1 : 0;
+}
+
+#else
+
+int go_GDALDatasetGetRelationshipNames(GDALDatasetH hDS, char ***ppapszNames) {
+    return -1;
+}
+
+int go_GDALDatasetGetRelationship(GDALDatasetH hDS, const char *pszName, goRelationship *psRel) {
+    return -1;
+}
+
+int go_GDALDatasetAddRelationship(GDALDatasetH hDS, const goRelationship *psRel,
+                                  char **ppszFailureReason) {
+    return -1;
+}
+
+int go_GDALDatasetUpdateRelationship(GDALDatasetH hDS, const goRelationship *psRel,
+                                     char **ppszFailureReason) {
+    return -1;
+}
+
+int go_GDALDatasetDeleteRelationship(GDALDatasetH hDS, const char *pszName,
+                                     char **ppszFailureReason) {
+    return -1;
+}
+
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 6, 0)
@@ -0,0 +1,53 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_GDAL_RELATIONSHIP_H_
+#define GO_GDAL_RELATIONSHIP_H_
+
+#include <gdal.h>
+
+// goRelationship mirrors the properties of a GDALRelationship. String lists
+// are allocated with CSLAddString. Strings are allocated with CPLStrdup when
+// filled by go_GDALDatasetGetRelationship, and by the caller otherwise.
+typedef struct {
+    char *pszName;
+    char *pszLeftTableName;
+    char *pszRightTableName;
+    char *pszMappingTableName;
+    int nCardinality;
+    int nType;
+    char **papszLeftTableFields;
+    char **papszRightTableFields;
+    char **papszLeftMappingTableFields;
+    char **papszRightMappingTableFields;
+    char *pszForwardPathLabel;
+    char *pszBackwardPathLabel;
+    char *pszRelatedTableType;
+} goRelationship;
+
+// go_FreeRelationship frees the members of psRel filled by
+// go_GDALDatasetGetRelationship.
+void go_FreeRelationship(goRelationship *psRel);
+
+// go_GDALDatasetGetRelationshipNames stores the relationship names of hDS,
+// to be freed with CSLDestroy. It returns 0 on success and -1 when GDAL is
+// older than 3.6.
+int go_GDALDatasetGetRelationshipNames(GDALDatasetH hDS, char ***ppapszNames);
+
+// go_GDALDatasetGetRelationship fills psRel with the relationship pszName of
+// hDS. It returns 1 when found, 0 otherwise and -1 when GDAL is older than
+// 3.6.
+int go_GDALDatasetGetRelationship(GDALDatasetH hDS, const char *pszName, goRelationship *psRel);
+
+// go_GDALDatasetAddRelationship, go_GDALDatasetUpdateRelationship and
+// go_GDALDatasetDeleteRelationship return 1 on success, 0 on failure with the
+// reason stored in *ppszFailureReason, to be freed with CPLFree, and -1 when
+// GDAL is older than 3.6.
+int go_GDALDatasetAddRelationship(GDALDatasetH hDS, const goRelationship *psRel,
+                                  char **ppszFailureReason);
+int go_GDALDatasetUpdateRelationship(GDALDatasetH hDS, const goRelationship *psRel,
+                                     char **ppszFailureReason);
+int go_GDALDatasetDeleteRelationship(GDALDatasetH hDS, const char *pszName,
+                                     char **ppszFailureReason);
+
+#endif  // GO_GDAL_RELATIONSHIP_H_
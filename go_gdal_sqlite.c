@@ -0,0 +1,85 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_gdal_sqlite.h"
+
+#include <cpl_conv.h>
+#include <cpl_error.h>
+
+// Result codes of sqlite3.h, which is not required to build.
+#define GO_SQLITE_OK 0
+#define GO_SQLITE_DONE 101
+
+typedef struct {
+    void *hDB;
+    void *hStmt;
+    int (*pfnPrepare)(void *, const char *, int, void **, const char **);
+    int (*pfnBindInt)(void *, int, int);
+    int (*pfnBindBlob)(void *, int, const void *, int, void (*)(void *));
+    int (*pfnStep)(void *);
+    int (*pfnReset)(void *);
+    int (*pfnClearBindings)(void *);
+    int (*pfnFinalize)(void *);
+    const char *(*pfnErrmsg)(void *);
+} goSQLiteStatement;
+
+static int loadSQLite(goSQLiteStatement *psStatement) {
+    psStatement->pfnPrepare = CPLGetSymbol(NULL, "sqlite3_prepare_v2");
+    psStatement->pfnBindInt = CPLGetSymbol(NULL, "sqlite3_bind_int");
+    psStatement->pfnBindBlob = CPLGetSymbol(NULL, "sqlite3_bind_blob");
+    psStatement->pfnStep = CPLGetSymbol(NULL, "sqlite3_step");
+    psStatement->pfnReset = CPLGetSymbol(NULL, "sqlite3_reset");
+    psStatement->pfnClearBindings = CPLGetSymbol(NULL, "sqlite3_clear_bindings");
+    psStatement->pfnFinalize = CPLGetSymbol(NULL, "sqlite3_finalize");
+    psStatement->pfnErrmsg = CPLGetSymbol(NULL, "sqlite3_errmsg");
+    return psStatement->pfnPrepare != NULL && psStatement->pfnBindInt != NULL &&
+           psStatement->pfnBindBlob != NULL && psStatement->pfnStep != NULL &&
+           psStatement->pfnReset != NULL && psStatement->pfnClearBindings != NULL &&
+           psStatement->pfnFinalize != NULL && psStatement->pfnErrmsg != NULL;
+}
+
+void *go_SQLitePrepare(GDALDatasetH hDS, const char *pszSQL) {
+    void *hDB = GDALGetInternalHandle(hDS, "SQLITE_HANDLE");
+    if (hDB == NULL) {
+        CPLError(CE_Failure, CPLE_NotSupported, "dataset has no SQLite connection");
+        return NULL;
+    }
+    goSQLiteStatement *psStatement = CPLCalloc(1, sizeof(goSQLiteStatement));
+    psStatement->hDB = hDB;
+    if (!loadSQLite(psStatement)) {
+        CPLError(CE_Failure, CPLE_NotSupported, "SQLite functions are not available");
+        CPLFree(psStatement);
+        return NULL;
+    }
+    if (psStatement->pfnPrepare(hDB, pszSQL, -1, &psStatement->hStmt, NULL) != GO_SQLITE_OK) {
+        CPLError(CE_Failure, CPLE_AppDefined, "%s", psStatement->pfnErrmsg(hDB));
+        CPLFree(psStatement);
+        return NULL;
+    }
+    return psStatement;
+}
+
+int go_SQLiteInsertTile(void *hStatement, int nZoom, int nCol, int nRow, const void *pData,
+                        int nSize) {
+    goSQLiteStatement *psStatement = hStatement;
+    void *hStmt = psStatement->hStmt;
+    // A NULL destructor (SQLITE_STATIC) does not copy pData, which stays
+    // valid until the statement is reset below.
+    int nResult = psStatement->pfnBindInt(hStmt, 1, nZoom);
+    if (nResult == GO_SQLITE_OK) nResult = psStatement->pfnBindInt(hStmt, 2, nCol);
+    if (nResult == GO_SQLITE_OK) nResult = psStatement->pfnBindInt(hStmt, 3, nRow);
+    if (nResult == GO_SQLITE_OK) nResult = psStatement->pfnBindBlob(hStmt, 4, pData, nSize, NULL);
+    if (nResult == GO_SQLITE_OK) nResult = psStatement->pfnStep(hStmt);
+    if (nResult != GO_SQLITE_DONE) {
+        CPLError(CE_Failure, CPLE_AppDefined, "%s", psStatement->pfnErrmsg(psStatement->hDB));
+    }
+    psStatement->pfnReset(hStmt);
+    psStatement->pfnClearBindings(hStmt);
+    return nResult == GO_SQLITE_DONE;
+}
+
+void go_SQLiteFinalize(void *hStatement) {
+    goSQLiteStatement *psStatement = hStatement;
+    psStatement->pfnFinalize(psStatement->hStmt);
+    CPLFree(psStatement);
+}
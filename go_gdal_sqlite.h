@@ -0,0 +1,24 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_GDAL_SQLITE_H_
+#define GO_GDAL_SQLITE_H_
+
+#include <gdal.h>
+
+// go_SQLitePrepare prepares pszSQL on the SQLite connection of hDS, as
+// returned by GDALGetInternalHandle(hDS, "SQLITE_HANDLE"). The SQLite
+// functions are looked up in the library GDAL is linked with. It returns
+// NULL and reports a CPL error on failure.
+void *go_SQLitePrepare(GDALDatasetH hDS, const char *pszSQL);
+
+// go_SQLiteInsertTile binds a tile to the four parameters of a statement
+// prepared by go_SQLitePrepare and runs it. It returns 1 on success, 0
+// otherwise with a CPL error reported.
+int go_SQLiteInsertTile(void *hStatement, int nZoom, int nCol, int nRow, const void *pData,
+                        int nSize);
+
+// go_SQLiteFinalize releases a statement prepared by go_SQLitePrepare.
+void go_SQLiteFinalize(void *hStatement);
+
+#endif  // GO_GDAL_SQLITE_H_
@@ -0,0 +1,28 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_gdal_subdataset.h"
+
+#include <gdal.h>
+#include <gdal_version.h>
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 8, 0)
+
+int go_GetSubdatasetInfo(const char *pszFileName, char **ppszPath, char **ppszComponent) {
+    GDALSubdatasetInfoH hInfo = GDALGetSubdatasetInfo(pszFileName);
+    if (hInfo == NULL) {
+        return 0;
+    }
+    *ppszPath = GDALSubdatasetInfoGetPathComponent(hInfo);
+    *ppszComponent = GDALSubdatasetInfoGetSubdatasetComponent(hInfo);
+    GDALDestroySubdatasetInfo(hInfo);
+    return 1;
+}
+
+#else
+
+int go_GetSubdatasetInfo(const char *pszFileName, char **ppszPath, char **ppszComponent) {
+    return -1;
+}
+
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 8, 0)
@@ -0,0 +1,12 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_GDAL_SUBDATASET_H_
+#define GO_GDAL_SUBDATASET_H_
+
+// go_GetSubdatasetInfo splits a subdataset name into its path and subdataset
+// components, to be freed with CPLFree. It returns 1 on success, 0 when the
+// name is not recognized and -1 when GDAL is older than 3.8.
+int go_GetSubdatasetInfo(const char *pszFileName, char **ppszPath, char **ppszComponent);
+
+#endif  // GO_GDAL_SUBDATASET_H_
@@ -0,0 +1,26 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_gdal_tiles.h"
+
+#include <gdal_alg.h>
+#include <cpl_string.h>
+
+int go_TransformedExtent(GDALDatasetH hDS, const char *pszDstSRS, double *padfExtent,
+                         double *pdfResolution) {
+    char **papszOptions = CSLSetNameValue(NULL, "DST_SRS", pszDstSRS);
+    void *hTransformer = GDALCreateGenImgProjTransformer2(hDS, NULL, papszOptions);
+    CSLDestroy(papszOptions);
+    if (hTransformer == NULL) {
+        return 0;
+    }
+
+    double adfGeoTransform[6];
+    int nPixels = 0;
+    int nLines = 0;
+    CPLErr eErr = GDALSuggestedWarpOutput2(hDS, GDALGenImgProjTransform, hTransformer,
+                                           adfGeoTransform, &nPixels, &nLines, padfExtent, 0);
+    GDALDestroyGenImgProjTransformer(hTransformer);
+    *pdfResolution = adfGeoTransform[1];
+    return eErr == CE_None;
+}
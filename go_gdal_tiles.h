@@ -0,0 +1,15 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_GDAL_TILES_H_
+#define GO_GDAL_TILES_H_
+
+#include <gdal.h>
+
+// go_TransformedExtent computes the extent (min x, min y, max x, max y) of
+// hDS once warped to pszDstSRS, along with the suggested output resolution.
+// It returns 1 on success, 0 otherwise.
+int go_TransformedExtent(GDALDatasetH hDS, const char *pszDstSRS, double *padfExtent,
+                         double *pdfResolution);
+
+#endif  // GO_GDAL_TILES_H_
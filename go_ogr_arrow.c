@@ -0,0 +1,78 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_ogr_arrow.h"
+
+#include <gdal_version.h>
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 6, 0)
+
+int go_OGR_L_GetArrowStream(OGRLayerH hLayer, struct ArrowArrayStream *psStream,
+                            char **papszOptions) {
+    return OGR_L_GetArrowStream(hLayer, psStream, papszOptions) ? 1 : 0;
+}
+
+#else
+
+int go_OGR_L_GetArrowStream(OGRLayerH hLayer, struct ArrowArrayStream *psStream,
+                            char **papszOptions) {
+    return -1;
+}
+
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 6, 0)
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 8, 0)
+
+int go_OGR_L_WriteArrowBatch(OGRLayerH hLayer, const struct ArrowSchema *psSchema,
+                             struct ArrowArray *psArray, char **papszOptions) {
+    return OGR_L_WriteArrowBatch(hLayer, psSchema, psArray, papszOptions) ? 1 : 0;
+}
+
+int go_OGR_L_CreateFieldFromArrowSchema(OGRLayerH hLayer, const struct ArrowSchema *psSchema,
+                                        char **papszOptions) {
+    return OGR_L_CreateFieldFromArrowSchema(hLayer, psSchema, papszOptions) ? 1 : 0;
+}
+
+#else
+
+int go_OGR_L_WriteArrowBatch(OGRLayerH hLayer, const struct ArrowSchema *psSchema,
+                             struct ArrowArray *psArray, char **papszOptions) {
+    return -1;
+}
+
+int go_OGR_L_CreateFieldFromArrowSchema(OGRLayerH hLayer, const struct ArrowSchema *psSchema,
+                                        char **papszOptions) {
+    return -1;
+}
+
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 8, 0)
+
+int go_ArrowArrayStreamGetSchema(struct ArrowArrayStream *psStream, struct ArrowSchema *psOut) {
+    return psStream->get_schema(psStream, psOut);
+}
+
+int go_ArrowArrayStreamGetNext(struct ArrowArrayStream *psStream, struct ArrowArray *psOut) {
+    return psStream->get_next(psStream, psOut);
+}
+
+const char *go_ArrowArrayStreamGetLastError(struct ArrowArrayStream *psStream) {
+    return psStream->get_last_error(psStream);
+}
+
+void go_ArrowArrayStreamRelease(struct ArrowArrayStream *psStream) {
+    if (psStream->release != NULL) {
+        psStream->release(psStream);
+    }
+}
+
+void go_ArrowSchemaRelease(struct ArrowSchema *psSchema) {
+    if (psSchema->release != NULL) {
+        psSchema->release(psSchema);
+    }
+}
+
+void go_ArrowArrayRelease(struct ArrowArray *psArray) {
+    if (psArray->release != NULL) {
+        psArray->release(psArray);
+    }
+}
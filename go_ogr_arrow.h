@@ -0,0 +1,89 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_OGR_ARROW_H_
+#define GO_OGR_ARROW_H_
+
+#include <stdint.h>
+#include <ogr_api.h>
+
+// The Arrow C data interface structures, as published by the Apache Arrow
+// project. GDAL 3.6 and newer define them in ogr_recordbatch.h.
+#ifndef ARROW_C_DATA_INTERFACE
+#define ARROW_C_DATA_INTERFACE
+
+#define ARROW_FLAG_DICTIONARY_ORDERED 1
+#define ARROW_FLAG_NULLABLE 2
+#define ARROW_FLAG_MAP_KEYS_SORTED 4
+
+struct ArrowSchema {
+    const char *format;
+    const char *name;
+    const char *metadata;
+    int64_t flags;
+    int64_t n_children;
+    struct ArrowSchema **children;
+    struct ArrowSchema *dictionary;
+    void (*release)(struct ArrowSchema *);
+    void *private_data;
+};
+
+struct ArrowArray {
+    int64_t length;
+    int64_t null_count;
+    int64_t offset;
+    int64_t n_buffers;
+    int64_t n_children;
+    const void **buffers;
+    struct ArrowArray **children;
+    struct ArrowArray *dictionary;
+    void (*release)(struct ArrowArray *);
+    void *private_data;
+};
+
+#endif  // ARROW_C_DATA_INTERFACE
+
+#ifndef ARROW_C_STREAM_INTERFACE
+#define ARROW_C_STREAM_INTERFACE
+
+struct ArrowArrayStream {
+    int (*get_schema)(struct ArrowArrayStream *, struct ArrowSchema *out);
+    int (*get_next)(struct ArrowArrayStream *, struct ArrowArray *out);
+    const char *(*get_last_error)(struct ArrowArrayStream *);
+    void (*release)(struct ArrowArrayStream *);
+    void *private_data;
+};
+
+#endif  // ARROW_C_STREAM_INTERFACE
+
+// go_OGR_L_GetArrowStream fills psStream with an Arrow stream reading hLayer.
+// It returns 1 on success, 0 on failure and -1 when GDAL is older than 3.6.
+int go_OGR_L_GetArrowStream(OGRLayerH hLayer, struct ArrowArrayStream *psStream,
+                            char **papszOptions);
+
+// go_OGR_L_WriteArrowBatch writes the record batch psArray, described by
+// psSchema, to hLayer. It returns 1 on success, 0 on failure and -1 when
+// GDAL is older than 3.8.
+int go_OGR_L_WriteArrowBatch(OGRLayerH hLayer, const struct ArrowSchema *psSchema,
+                             struct ArrowArray *psArray, char **papszOptions);
+
+// go_OGR_L_CreateFieldFromArrowSchema creates a field of hLayer from the
+// Arrow field psSchema. It returns 1 on success, 0 on failure and -1 when
+// GDAL is older than 3.8.
+int go_OGR_L_CreateFieldFromArrowSchema(OGRLayerH hLayer, const struct ArrowSchema *psSchema,
+                                        char **papszOptions);
+
+// go_ArrowArrayStreamGetSchema, go_ArrowArrayStreamGetNext and
+// go_ArrowArrayStreamGetLastError call the callbacks of psStream.
+int go_ArrowArrayStreamGetSchema(struct ArrowArrayStream *psStream, struct ArrowSchema *psOut);
+int go_ArrowArrayStreamGetNext(struct ArrowArrayStream *psStream, struct ArrowArray *psOut);
+const char *go_ArrowArrayStreamGetLastError(struct ArrowArrayStream *psStream);
+
+// go_ArrowArrayStreamRelease, go_ArrowSchemaRelease and go_ArrowArrayRelease
+// call the release callback of a structure that has not been released or
+// moved yet.
+void go_ArrowArrayStreamRelease(struct ArrowArrayStream *psStream);
+void go_ArrowSchemaRelease(struct ArrowSchema *psSchema);
+void go_ArrowArrayRelease(struct ArrowArray *psArray);
+
+#endif  // GO_OGR_ARROW_H_
@@ -0,0 +1,39 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_OGR_CAPABILITIES_H_
+#define GO_OGR_CAPABILITIES_H_
+
+#include <ogr_core.h>
+
+// Capabilities added after GDAL 3.0 are defined here for older headers, so
+// that the Go constants build against any supported GDAL version.
+#ifndef ODsCAddFieldDomain
+#define ODsCAddFieldDomain "AddFieldDomain"
+#endif
+#ifndef ODsCDeleteFieldDomain
+#define ODsCDeleteFieldDomain "DeleteFieldDomain"
+#endif
+#ifndef ODsCUpdateFieldDomain
+#define ODsCUpdateFieldDomain "UpdateFieldDomain"
+#endif
+#ifndef ODsCZGeometries
+#define ODsCZGeometries "ZGeometries"
+#endif
+#ifndef OLCZGeometries
+#define OLCZGeometries "ZGeometries"
+#endif
+#ifndef OLCAlterGeomFieldDefn
+#define OLCAlterGeomFieldDefn "AlterGeomFieldDefn"
+#endif
+#ifndef OLCUpsertFeature
+#define OLCUpsertFeature "UpsertFeature"
+#endif
+#ifndef OLCFastGetArrowStream
+#define OLCFastGetArrowStream "FastGetArrowStream"
+#endif
+#ifndef OLCFastWriteArrowBatch
+#define OLCFastWriteArrowBatch "FastWriteArrowBatch"
+#endif
+
+#endif  // GO_OGR_CAPABILITIES_H_
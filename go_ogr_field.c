@@ -0,0 +1,29 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_ogr_field.h"
+
+#include <gdal_version.h>
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 7, 0)
+
+const char *go_OGR_Fld_GetComment(OGRFieldDefnH hDefn) {
+    return OGR_Fld_GetComment(hDefn);
+}
+
+int go_OGR_Fld_SetComment(OGRFieldDefnH hDefn, const char *pszComment) {
+    OGR_Fld_SetComment(hDefn, pszComment);
+    return 0;
+}
+
+#else
+
+const char *go_OGR_Fld_GetComment(OGRFieldDefnH hDefn) {
+    return NULL;
+}
+
+int go_OGR_Fld_SetComment(OGRFieldDefnH hDefn, const char *pszComment) {
+    return -1;
+}
+
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 7, 0)
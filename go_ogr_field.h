@@ -0,0 +1,17 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_OGR_FIELD_H_
+#define GO_OGR_FIELD_H_
+
+#include <ogr_api.h>
+
+// go_OGR_Fld_GetComment returns the comment of hDefn, or NULL when GDAL is
+// older than 3.7.
+const char *go_OGR_Fld_GetComment(OGRFieldDefnH hDefn);
+
+// go_OGR_Fld_SetComment sets the comment of hDefn. It returns 0 on success
+// and -1 when GDAL is older than 3.7.
+int go_OGR_Fld_SetComment(OGRFieldDefnH hDefn, const char *pszComment);
+
+#endif  // GO_OGR_FIELD_H_
@@ -0,0 +1,106 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_ogr_fielddomain.h"
+
+#include <gdal_version.h>
+#include <cpl_conv.h>
+
+OGRFieldDomainH go_CodedFldDomain_Create(const char *pszName, const char *pszDescription,
+                                         OGRFieldType eFieldType, OGRFieldSubType eSubType,
+                                         char **papszCodes, char **papszValues, int nCount) {
+    OGRCodedValue *pasValues = (OGRCodedValue *)CPLCalloc(nCount + 1, sizeof(OGRCodedValue));
+    for (int i = 0; i < nCount; i++) {
+        pasValues[i].pszCode = papszCodes[i];
+        pasValues[i].pszValue = papszValues[i];
+    }
+    OGRFieldDomainH hDomain =
+        OGR_CodedFldDomain_Create(pszName, pszDescription, eFieldType, eSubType, pasValues);
+    CPLFree(pasValues);
+    return hDomain;
+}
+
+void go_OGRField_SetInteger(OGRField *psField, int nValue) {
+    psField->Integer = nValue;
+}
+
+void go_OGRField_SetInteger64(OGRField *psField, GIntBig nValue) {
+    psField->Integer64 = nValue;
+}
+
+void go_OGRField_SetReal(OGRField *psField, double dfValue) {
+    psField->Real = dfValue;
+}
+
+void go_OGRField_SetDateTime(OGRField *psField, int nYear, int nMonth, int nDay, int nHour,
+                             int nMinute, float fSecond, int nTZFlag) {
+    psField->Date.Year = (GInt16)nYear;
+    psField->Date.Month = (GByte)nMonth;
+    psField->Date.Day = (GByte)nDay;
+    psField->Date.Hour = (GByte)nHour;
+    psField->Date.Minute = (GByte)nMinute;
+    psField->Date.Second = fSecond;
+    psField->Date.TZFlag = (GByte)nTZFlag;
+}
+
+int go_OGRField_IsUnset(const OGRField *psField) {
+    return psField == NULL || OGR_RawField_IsUnset(psField);
+}
+
+int go_OGRField_GetInteger(const OGRField *psField) {
+    return psField->Integer;
+}
+
+GIntBig go_OGRField_GetInteger64(const OGRField *psField) {
+    return psField->Integer64;
+}
+
+double go_OGRField_GetReal(const OGRField *psField) {
+    return psField->Real;
+}
+
+void go_OGRField_GetDateTime(const OGRField *psField, int *pnYear, int *pnMonth, int *pnDay,
+                             int *pnHour, int *pnMinute, float *pfSecond, int *pnTZFlag) {
+    *pnYear = psField->Date.Year;
+    *pnMonth = psField->Date.Month;
+    *pnDay = psField->Date.Day;
+    *pnHour = psField->Date.Hour;
+    *pnMinute = psField->Date.Minute;
+    *pfSecond = psField->Date.Second;
+    *pnTZFlag = psField->Date.TZFlag;
+}
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 5, 0)
+
+int go_GDALDatasetGetFieldDomainNames(GDALDatasetH hDS, char ***ppapszNames) {
+    *ppapszNames = GDALDatasetGetFieldDomainNames(hDS, NULL);
+    return 0;
+}
+
+int go_GDALDatasetUpdateFieldDomain(GDALDatasetH hDS, OGRFieldDomainH hDomain,
+                                    char **ppszFailureReason) {
+    return GDALDatasetUpdateFieldDomain(hDS, hDomain, ppszFailureReason) ? 1 : 0;
+}
+
+int go_GDALDatasetDeleteFieldDomain(GDALDatasetH hDS, const char *pszName,
+                                    char **ppszFailureReason) {
+    return GDALDatasetDeleteFieldDomain(hDS, pszName, ppszFailureReason) ? 1 : 0;
+}
+
+#else
+
+int go_GDALDatasetGetFieldDomainNames(GDALDatasetH hDS, char ***ppapszNames) {
+    return -1;
+}
+
+int go_GDALDatasetUpdateFieldDomain(GDALDatasetH hDS, OGRFieldDomainH hDomain,
+                                    char **ppszFailureReason) {
+    return -1;
+}
+
+int go_GDALDatasetDeleteFieldDomain(GDALDatasetH hDS, const char *pszName,
+                                    char **ppszFailureReason) {
+    return -1;
+}
+
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 5, 0)
@@ -0,0 +1,47 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_OGR_FIELDDOMAIN_H_
+#define GO_OGR_FIELDDOMAIN_H_
+
+#include <gdal.h>
+#include <ogr_api.h>
+
+// go_CodedFldDomain_Create creates a coded value domain from nCount codes
+// and values. A NULL value leaves the code without description.
+OGRFieldDomainH go_CodedFldDomain_Create(const char *pszName, const char *pszDescription,
+                                         OGRFieldType eFieldType, OGRFieldSubType eSubType,
+                                         char **papszCodes, char **papszValues, int nCount);
+
+// go_OGRField_SetInteger, go_OGRField_SetInteger64,
+// go_OGRField_SetReal and go_OGRField_SetDateTime fill psField.
+void go_OGRField_SetInteger(OGRField *psField, int nValue);
+void go_OGRField_SetInteger64(OGRField *psField, GIntBig nValue);
+void go_OGRField_SetReal(OGRField *psField, double dfValue);
+void go_OGRField_SetDateTime(OGRField *psField, int nYear, int nMonth, int nDay, int nHour,
+                             int nMinute, float fSecond, int nTZFlag);
+
+// go_OGRField_IsUnset, go_OGRField_GetInteger, go_OGRField_GetInteger64,
+// go_OGRField_GetReal and go_OGRField_GetDateTime read psField.
+int go_OGRField_IsUnset(const OGRField *psField);
+int go_OGRField_GetInteger(const OGRField *psField);
+GIntBig go_OGRField_GetInteger64(const OGRField *psField);
+double go_OGRField_GetReal(const OGRField *psField);
+void go_OGRField_GetDateTime(const OGRField *psField, int *pnYear, int *pnMonth, int *pnDay,
+                             int *pnHour, int *pnMinute, float *pfSecond, int *pnTZFlag);
+
+// go_GDALDatasetGetFieldDomainNames stores the domain names of hDS, to be
+// freed with CSLDestroy. It returns 0 on success and -1 when GDAL is older
+// than 3.5.
+int go_GDALDatasetGetFieldDomainNames(GDALDatasetH hDS, char ***ppapszNames);
+
+// go_GDALDatasetUpdateFieldDomain and go_GDALDatasetDeleteFieldDomain
+// return 1 on success, 0 on failure with the reason stored in
+// *ppszFailureReason, to be freed with CPLFree, and -1 when GDAL is older
+// than 3.5.
+int go_GDALDatasetUpdateFieldDomain(GDALDatasetH hDS, OGRFieldDomainH hDomain,
+                                    char **ppszFailureReason);
+int go_GDALDatasetDeleteFieldDomain(GDALDatasetH hDS, const char *pszName,
+                                    char **ppszFailureReason);
+
+#endif  // GO_OGR_FIELDDOMAIN_H_
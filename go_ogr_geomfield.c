@@ -0,0 +1,40 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_ogr_geomfield.h"
+
+#include <gdal_version.h>
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 9, 0)
+
+int go_OGR_GFld_GetCoordinatePrecision(OGRGeomFieldDefnH hDefn, double *pdfXY, double *pdfZ,
+                                       double *pdfM) {
+    OGRGeomCoordinatePrecisionH hPrec = OGR_GFld_GetCoordinatePrecision(hDefn);
+    *pdfXY = OGRGeomCoordinatePrecisionGetXYResolution(hPrec);
+    *pdfZ = OGRGeomCoordinatePrecisionGetZResolution(hPrec);
+    *pdfM = OGRGeomCoordinatePrecisionGetMResolution(hPrec);
+    return 0;
+}
+
+int go_OGR_GFld_SetCoordinatePrecision(OGRGeomFieldDefnH hDefn, double dfXY, double dfZ,
+                                       double dfM) {
+    OGRGeomCoordinatePrecisionH hPrec = OGRGeomCoordinatePrecisionCreate();
+    OGRGeomCoordinatePrecisionSet(hPrec, dfXY, dfZ, dfM);
+    OGR_GFld_SetCoordinatePrecision(hDefn, hPrec);
+    OGRGeomCoordinatePrecisionDestroy(hPrec);
+    return 0;
+}
+
+#else
+
+int go_OGR_GFld_GetCoordinatePrecision(OGRGeomFieldDefnH hDefn, double *pdfXY, double *pdfZ,
+                                       double *pdfM) {
+    return -1;
+}
+
+int go_OGR_GFld_SetCoordinatePrecision(OGRGeomFieldDefnH hDefn, double dfXY, double dfZ,
+                                       double dfM) {
+    return -1;
+}
+
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 9, 0)
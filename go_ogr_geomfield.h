@@ -0,0 +1,20 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_OGR_GEOMFIELD_H_
+#define GO_OGR_GEOMFIELD_H_
+
+#include <ogr_api.h>
+
+// go_OGR_GFld_GetCoordinatePrecision stores the XY, Z and M resolutions of
+// hDefn, 0 when unknown. It returns 0 on success and -1 when GDAL is older
+// than 3.9.
+int go_OGR_GFld_GetCoordinatePrecision(OGRGeomFieldDefnH hDefn, double *pdfXY, double *pdfZ,
+                                       double *pdfM);
+
+// go_OGR_GFld_SetCoordinatePrecision sets the XY, Z and M resolutions of
+// hDefn. It returns 0 on success and -1 when GDAL is older than 3.9.
+int go_OGR_GFld_SetCoordinatePrecision(OGRGeomFieldDefnH hDefn, double dfXY, double dfZ,
+                                       double dfM);
+
+#endif  // GO_OGR_GEOMFIELD_H_
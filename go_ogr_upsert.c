@@ -0,0 +1,21 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#include "go_ogr_upsert.h"
+
+#include <gdal_version.h>
+
+#if GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 6, 0)
+
+int go_OGR_L_UpsertFeature(OGRLayerH hLayer, OGRFeatureH hFeat, OGRErr *peErr) {
+    *peErr = OGR_L_UpsertFeature(hLayer, hFeat);
+    return 1;
+}
+
+#else
+
+int go_OGR_L_UpsertFeature(OGRLayerH hLayer, OGRFeatureH hFeat, OGRErr *peErr) {
+    return -1;
+}
+
+#endif // GDAL_VERSION_NUM >= GDAL_COMPUTE_VERSION(3, 6, 0)
@@ -0,0 +1,14 @@
+// Copyright 2011 go-gdal. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+#ifndef GO_OGR_UPSERT_H_
+#define GO_OGR_UPSERT_H_
+
+#include <ogr_api.h>
+
+// go_OGR_L_UpsertFeature inserts hFeat into hLayer or replaces the feature
+// with the same FID, storing the result in *peErr. It returns 1 when the
+// call was made and -1 when GDAL is older than 3.6.
+int go_OGR_L_UpsertFeature(OGRLayerH hLayer, OGRFeatureH hFeat, OGRErr *peErr);
+
+#endif  // GO_OGR_UPSERT_H_
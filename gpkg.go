@@ -0,0 +1,303 @@
+package gdal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrTileNotFound is returned when a requested tile is not stored.
+var ErrTileNotFound = errors.New("tile not found")
+
+// GPKGTileMatrix describes one zoom level of a GeoPackage tile pyramid.
+type GPKGTileMatrix struct {
+	ZoomLevel                 int
+	MatrixWidth, MatrixHeight int
+	TileWidth, TileHeight     int
+	PixelXSize, PixelYSize    float64
+}
+
+// GPKGTileMatrixSet describes the tile pyramid of a GeoPackage raster table.
+type GPKGTileMatrixSet struct {
+	Table string
+	// SRSID is the srs_id of the table in gpkg_spatial_ref_sys.
+	SRSID                  int
+	MinX, MinY, MaxX, MaxY float64
+	// Matrices lists the zoom levels, sorted by increasing zoom level.
+	Matrices []GPKGTileMatrix
+}
+
+// GPKGTiles gives tile-level access to a GeoPackage raster table. Tile rows
+// are counted from the top of the matrix.
+type GPKGTiles struct {
+	Table  string
+	ds     DataSource
+	writer *tileBlobWriter
+}
+
+func newGPKGTiles(ds DataSource, table string) *GPKGTiles {
+	return &GPKGTiles{Table: table, ds: ds, writer: newTileBlobWriter(ds, table)}
+}
+
+// OpenGPKGTiles opens the tile pyramid table of the GeoPackage filename,
+// for writing when update is true.
+func OpenGPKGTiles(filename, table string, update bool) (*GPKGTiles, error) {
+	mode := 0
+	if update {
+		mode = 1
+	}
+	ds, ok := OGRDriverByName(OGRDriverNameGPKG).Open(filename, mode)
+	if !ok {
+		return nil, fmt.Errorf("error: failed to open GeoPackage %q", filename)
+	}
+	count, err := queryInt(ds, fmt.Sprintf(
+		"SELECT COUNT(*) FROM gpkg_contents WHERE data_type IN ('tiles', '2d-gridded-coverage') AND table_name = %s",
+		sqlQuote(table)))
+	if err == nil && count == 0 {
+		err = fmt.Errorf("error: %q is not a tile table of %q", table, filename)
+	}
+	if err != nil {
+		ds.Destroy()
+		return nil, err
+	}
+	return newGPKGTiles(ds, table), nil
+}
+
+// TileMatrixSet returns the tile matrix set and zoom levels of the table.
+func (tiles *GPKGTiles) TileMatrixSet() (GPKGTileMatrixSet, error) {
+	set := GPKGTileMatrixSet{Table: tiles.Table}
+	found := false
+	err := queryEach(tiles.ds, fmt.Sprintf(
+		"SELECT srs_id, min_x, min_y, max_x, max_y FROM gpkg_tile_matrix_set WHERE table_name = %s",
+		sqlQuote(tiles.Table)), func(feature Feature) error {
+		found = true
+		set.SRSID = feature.FieldAsInteger(0)
+		set.MinX = feature.FieldAsFloat64(1)
+		set.MinY = feature.FieldAsFloat64(2)
+		set.MaxX = feature.FieldAsFloat64(3)
+		set.MaxY = feature.FieldAsFloat64(4)
+		return nil
+	})
+	if err != nil {
+		return set, err
+	}
+	if !found {
+		return set, fmt.Errorf("error: no tile matrix set for table %q", tiles.Table)
+	}
+
+	err = queryEach(tiles.ds, fmt.Sprintf(
+		`SELECT zoom_level, matrix_width, matrix_height, tile_width, tile_height, pixel_x_size, pixel_y_size
+		FROM gpkg_tile_matrix WHERE table_name = %s ORDER BY zoom_level`,
+		sqlQuote(tiles.Table)), func(feature Feature) error {
+		set.Matrices = append(set.Matrices, GPKGTileMatrix{
+			ZoomLevel:    feature.FieldAsInteger(0),
+			MatrixWidth:  feature.FieldAsInteger(1),
+			MatrixHeight: feature.FieldAsInteger(2),
+			TileWidth:    feature.FieldAsInteger(3),
+			TileHeight:   feature.FieldAsInteger(4),
+			PixelXSize:   feature.FieldAsFloat64(5),
+			PixelYSize:   feature.FieldAsFloat64(6),
+		})
+		return nil
+	})
+	return set, err
+}
+
+// ZoomLevels returns the zoom levels holding at least one tile, sorted.
+func (tiles *GPKGTiles) ZoomLevels() ([]int, error) {
+	if err := tiles.writer.flush(); err != nil {
+		return nil, err
+	}
+	var levels []int
+	err := queryEach(tiles.ds, fmt.Sprintf(
+		"SELECT DISTINCT zoom_level FROM %s ORDER BY zoom_level", sqlIdentifier(tiles.Table)),
+		func(feature Feature) error {
+			levels = append(levels, feature.FieldAsInteger(0))
+			return nil
+		})
+	return levels, err
+}
+
+// tileWhere returns the SQL condition selecting a tile.
+func tileWhere(zoom, col, row int) string {
+	return "zoom_level = " + strconv.Itoa(zoom) +
+		" AND tile_column = " + strconv.Itoa(col) +
+		" AND tile_row = " + strconv.Itoa(row)
+}
+
+// HasTile reports whether the tile is stored.
+func (tiles *GPKGTiles) HasTile(zoom, col, row int) (bool, error) {
+	return tiles.writer.has(zoom, col, row)
+}
+
+// ReadTile returns the encoded image of a tile, or ErrTileNotFound.
+func (tiles *GPKGTiles) ReadTile(zoom, col, row int) ([]byte, error) {
+	if err := tiles.writer.flush(); err != nil {
+		return nil, err
+	}
+	var data []byte
+	err := queryEach(tiles.ds, fmt.Sprintf("SELECT tile_data FROM %s WHERE %s",
+		sqlIdentifier(tiles.Table), tileWhere(zoom, col, row)), func(feature Feature) error {
+		data = feature.FieldAsBinary(0)
+		return nil
+	})
+	if err == nil && data == nil {
+		err = ErrTileNotFound
+	}
+	return data, err
+}
+
+// WriteTile stores the encoded image of a tile, replacing any existing one.
+// The zoom level must be described in gpkg_tile_matrix. Tiles are committed
+// in batches; Close commits the last one.
+func (tiles *GPKGTiles) WriteTile(zoom, col, row int, data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("error: tile data is empty")
+	}
+	return tiles.writer.write(zoom, col, row, data)
+}
+
+// DeleteTile removes a tile.
+func (tiles *GPKGTiles) DeleteTile(zoom, col, row int) error {
+	if err := tiles.writer.flush(); err != nil {
+		return err
+	}
+	return execSQL(tiles.ds, fmt.Sprintf("DELETE FROM %s WHERE %s",
+		sqlIdentifier(tiles.Table), tileWhere(zoom, col, row)))
+}
+
+// Close commits the pending tiles and releases the GeoPackage.
+func (tiles *GPKGTiles) Close() error {
+	err := tiles.writer.close()
+	tiles.ds.Destroy()
+	return err
+}
+
+// GPKGTilingScheme is the tiling scheme of a GeoPackage raster.
+type GPKGTilingScheme string
+
+const (
+	GPKGTilingSchemeCustom                  = GPKGTilingScheme("CUSTOM")
+	GPKGTilingSchemeGoogleMapsCompatible    = GPKGTilingScheme("GoogleMapsCompatible")
+	GPKGTilingSchemeGoogleCRS84Quad         = GPKGTilingScheme("GoogleCRS84Quad")
+	GPKGTilingSchemeInspireCRS84Quad        = GPKGTilingScheme("InspireCRS84Quad")
+	GPKGTilingSchemePseudoTMSGlobalGeodetic = GPKGTilingScheme("PseudoTMS_GlobalGeodetic")
+	GPKGTilingSchemePseudoTMSGlobalMercator = GPKGTilingScheme("PseudoTMS_GlobalMercator")
+	GPKGTilingSchemeWebMercatorQuad         = GPKGTilingScheme("WebMercatorQuad")
+	GPKGTilingSchemeWorldCRS84Quad          = GPKGTilingScheme("WorldCRS84Quad")
+)
+
+// GPKGZoomLevelStrategy selects the zoom level of a raster created with a
+// tiling scheme other than CUSTOM.
+type GPKGZoomLevelStrategy string
+
+const (
+	// GPKGZoomLevelAuto picks the zoom level closest to the source resolution.
+	GPKGZoomLevelAuto = GPKGZoomLevelStrategy("AUTO")
+	// GPKGZoomLevelLower picks the zoom level just below the source resolution.
+	GPKGZoomLevelLower = GPKGZoomLevelStrategy("LOWER")
+	// GPKGZoomLevelUpper picks the zoom level just above the source resolution.
+	GPKGZoomLevelUpper = GPKGZoomLevelStrategy("UPPER")
+)
+
+// GPKGTileFormat is the image format of GeoPackage tiles.
+type GPKGTileFormat string
+
+const (
+	GPKGTileFormatAuto    = GPKGTileFormat("AUTO")
+	GPKGTileFormatPNGJPEG = GPKGTileFormat("PNG_JPEG")
+	GPKGTileFormatPNG     = GPKGTileFormat("PNG")
+	GPKGTileFormatPNG8    = GPKGTileFormat("PNG8")
+	GPKGTileFormatJPEG    = GPKGTileFormat("JPEG")
+	GPKGTileFormatWEBP    = GPKGTileFormat("WEBP")
+	GPKGTileFormatTIFF    = GPKGTileFormat("TIFF")
+)
+
+// GPKGRasterOptions describes how CreateGPKGRaster writes a raster table.
+// Zero values leave the corresponding GPKG driver option at its default.
+type GPKGRasterOptions struct {
+	// Table is the raster table name, derived from the file name by default.
+	Table       string
+	Identifier  string
+	Description string
+	// TilingScheme defaults to CUSTOM.
+	TilingScheme GPKGTilingScheme
+	// ZoomLevelStrategy applies to tiling schemes other than CUSTOM.
+	ZoomLevelStrategy GPKGZoomLevelStrategy
+	TileFormat        GPKGTileFormat
+	// Quality is the JPEG or WEBP quality, between 1 and 100.
+	Quality int
+	// BlockSize is the tile size of the CUSTOM tiling scheme.
+	BlockSize int
+	// Resampling is the method used to fit the tiling scheme, e.g. "BILINEAR".
+	Resampling string
+	// Append adds the table to an existing GeoPackage.
+	Append bool
+	// CreationOptions holds additional NAME=VALUE driver options.
+	CreationOptions []string
+}
+
+// creationOptions returns the GPKG driver creation options for opts.
+func (opts GPKGRasterOptions) creationOptions() ([]string, error) {
+	if opts.Quality < 0 || opts.Quality > 100 {
+		return nil, fmt.Errorf("error: quality %d must be between 1 and 100", opts.Quality)
+	}
+	if opts.BlockSize < 0 {
+		return nil, fmt.Errorf("error: block size %d must be positive", opts.BlockSize)
+	}
+	custom := opts.TilingScheme == "" || opts.TilingScheme == GPKGTilingSchemeCustom
+	if opts.ZoomLevelStrategy != "" && custom {
+		return nil, fmt.Errorf("error: a zoom level strategy needs a tiling scheme other than CUSTOM")
+	}
+	if opts.BlockSize > 0 && !custom {
+		return nil, fmt.Errorf("error: the block size is fixed by the %s tiling scheme", opts.TilingScheme)
+	}
+
+	var options []string
+	add := func(name, value string) {
+		if value != "" {
+			options = append(options, name+"="+value)
+		}
+	}
+	add("RASTER_TABLE", opts.Table)
+	add("RASTER_IDENTIFIER", opts.Identifier)
+	add("RASTER_DESCRIPTION", opts.Description)
+	add("TILING_SCHEME", string(opts.TilingScheme))
+	add("ZOOM_LEVEL_STRATEGY", string(opts.ZoomLevelStrategy))
+	add("TILE_FORMAT", string(opts.TileFormat))
+	if opts.Quality > 0 {
+		add("QUALITY", strconv.Itoa(opts.Quality))
+	}
+	if opts.BlockSize > 0 {
+		add("BLOCKSIZE", strconv.Itoa(opts.BlockSize))
+	}
+	add("RESAMPLING", opts.Resampling)
+	if opts.Append {
+		add("APPEND_SUBDATASET", "YES")
+	}
+	return append(options, opts.CreationOptions...), nil
+}
+
+// CreateGPKGRaster copies src to a raster table of the GeoPackage filename.
+// The returned dataset must be closed by the caller.
+func CreateGPKGRaster(
+	filename string,
+	src Dataset,
+	opts GPKGRasterOptions,
+	progress ProgressFunc,
+	data interface{},
+) (Dataset, error) {
+	options, err := opts.creationOptions()
+	if err != nil {
+		return Dataset{}, err
+	}
+	driver, err := GetDriverByName(DriverNameGPKG)
+	if err != nil {
+		return Dataset{}, err
+	}
+	ds := driver.CreateCopy(filename, src, 0, options, progress, data)
+	if ds.cval == nil {
+		return Dataset{}, fmt.Errorf("error: failed to create GeoPackage raster %q", filename)
+	}
+	return ds, nil
+}
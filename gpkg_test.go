@@ -0,0 +1,160 @@
+package gdal
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestGPKGTilesRead(t *testing.T) {
+	tiles, err := OpenGPKGTiles("testdata/tiles.gpkg", "tiles", false)
+	if err != nil {
+		t.Fatalf("OpenGPKGTiles: %v", err)
+	}
+	defer tiles.Close()
+
+	set, err := tiles.TileMatrixSet()
+	if err != nil {
+		t.Fatalf("TileMatrixSet: %v", err)
+	}
+	if set.SRSID != 4326 || set.MinX != -180 || set.MaxY != 90 {
+		t.Errorf("TileMatrixSet() = %+v", set)
+	}
+	want := []GPKGTileMatrix{{
+		ZoomLevel: 17, MatrixWidth: 262144, MatrixHeight: 131072, TileWidth: 256, TileHeight: 256,
+		PixelXSize: 5.364418029785156e-06, PixelYSize: 5.364418029785156e-06,
+	}}
+	if !reflect.DeepEqual(set.Matrices, want) {
+		t.Errorf("Matrices = %+v, want %+v", set.Matrices, want)
+	}
+
+	levels, err := tiles.ZoomLevels()
+	if err != nil || !reflect.DeepEqual(levels, []int{17}) {
+		t.Errorf("ZoomLevels() = %v, %v, want [17]", levels, err)
+	}
+
+	data, err := tiles.ReadTile(17, 132154, 33818)
+	if err != nil {
+		t.Fatalf("ReadTile: %v", err)
+	}
+	if len(data) != 11608 || !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		t.Errorf("ReadTile returned %d bytes, want a 11608 bytes JPEG", len(data))
+	}
+	if _, err := tiles.ReadTile(17, 0, 0); !errors.Is(err, ErrTileNotFound) {
+		t.Errorf("ReadTile(missing) error = %v, want ErrTileNotFound", err)
+	}
+
+	if _, err := OpenGPKGTiles("testdata/tiles.gpkg", "missing", false); err == nil {
+		t.Error("expected error for a missing table")
+	}
+}
+
+func TestCreateGPKGRasterAndWriteTiles(t *testing.T) {
+	src := createTileSource(t, "./tmp/gpkg_raster_source.tif")
+	defer src.Close()
+
+	filename := "./tmp/gpkg_raster.gpkg"
+	os.Remove(filename)
+	ds, err := CreateGPKGRaster(filename, src, GPKGRasterOptions{
+		Table:             "dem",
+		TilingScheme:      GPKGTilingSchemeGoogleMapsCompatible,
+		ZoomLevelStrategy: GPKGZoomLevelUpper,
+		TileFormat:        GPKGTileFormatPNG,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateGPKGRaster: %v", err)
+	}
+	ds.Close()
+
+	tiles, err := OpenGPKGTiles(filename, "dem", true)
+	if err != nil {
+		t.Fatalf("OpenGPKGTiles: %v", err)
+	}
+	defer tiles.Close()
+
+	set, err := tiles.TileMatrixSet()
+	if err != nil {
+		t.Fatalf("TileMatrixSet: %v", err)
+	}
+	if set.SRSID != 3857 || len(set.Matrices) == 0 {
+		t.Fatalf("TileMatrixSet() = %+v", set)
+	}
+	levels, err := tiles.ZoomLevels()
+	if err != nil || len(levels) == 0 {
+		t.Fatalf("ZoomLevels() = %v, %v", levels, err)
+	}
+	zoom := levels[len(levels)-1]
+	if zoom != WebMercatorQuad.ZoomForResolution(10) {
+		t.Errorf("zoom level = %d, want %d", zoom, WebMercatorQuad.ZoomForResolution(10))
+	}
+
+	col, row, _, _ := WebMercatorQuad.TileRange(zoom, 0, -2560, 2560, 0)
+	data, err := tiles.ReadTile(zoom, col, row)
+	if err != nil {
+		t.Fatalf("ReadTile: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("\x89PNG")) {
+		t.Error("tile is not a PNG image")
+	}
+
+	if err := tiles.WriteTile(zoom, 0, 0, data); err != nil {
+		t.Fatalf("WriteTile: %v", err)
+	}
+	if exists, err := tiles.HasTile(zoom, 0, 0); err != nil || !exists {
+		t.Errorf("HasTile after WriteTile = %v, %v", exists, err)
+	}
+	if written, err := tiles.ReadTile(zoom, 0, 0); err != nil || !bytes.Equal(written, data) {
+		t.Errorf("ReadTile after WriteTile = %d bytes, %v, want %d bytes", len(written), err, len(data))
+	}
+	if count, err := queryInt(tiles.ds, "SELECT COUNT(*) FROM gpkg_contents WHERE table_name <> 'dem'"); err != nil || count != 0 {
+		t.Errorf("WriteTile registered %d tables in gpkg_contents, %v", count, err)
+	}
+	if count, err := queryInt(tiles.ds, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' "+
+		"AND name <> 'dem' AND name NOT LIKE 'gpkg%' AND name NOT LIKE 'rtree%' AND name NOT LIKE 'sqlite%'"); err != nil || count != 0 {
+		t.Errorf("WriteTile created %d tables, %v", count, err)
+	}
+	if err := tiles.DeleteTile(zoom, 0, 0); err != nil {
+		t.Fatalf("DeleteTile: %v", err)
+	}
+	if exists, _ := tiles.HasTile(zoom, 0, 0); exists {
+		t.Error("tile still present after DeleteTile")
+	}
+}
+
+func TestGPKGRasterOptions(t *testing.T) {
+	got, err := GPKGRasterOptions{
+		Table:             "t",
+		TilingScheme:      GPKGTilingSchemeWorldCRS84Quad,
+		ZoomLevelStrategy: GPKGZoomLevelLower,
+		TileFormat:        GPKGTileFormatJPEG,
+		Quality:           90,
+		Append:            true,
+	}.creationOptions()
+	if err != nil {
+		t.Fatalf("creationOptions: %v", err)
+	}
+	want := []string{
+		"RASTER_TABLE=t",
+		"TILING_SCHEME=WorldCRS84Quad",
+		"ZOOM_LEVEL_STRATEGY=LOWER",
+		"TILE_FORMAT=JPEG",
+		"QUALITY=90",
+		"APPEND_SUBDATASET=YES",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("creationOptions() = %v, want %v", got, want)
+	}
+
+	invalid := []GPKGRasterOptions{
+		{ZoomLevelStrategy: GPKGZoomLevelAuto},
+		{TilingScheme: GPKGTilingSchemeGoogleMapsCompatible, BlockSize: 512},
+		{Quality: 200},
+	}
+	for _, opts := range invalid {
+		if _, err := opts.creationOptions(); err == nil {
+			t.Errorf("creationOptions(%+v): expected error", opts)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+*/
+import "C"
+import (
+	"fmt"
+	"iter"
+	"runtime"
+)
+
+// nextFeature returns the next feature of layer, nil at the end of the
+// layer, or the error GDAL reported while reading.
+func (layer Layer) nextFeature() (*Feature, error) {
+	// CPL errors are thread local.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	C.CPLErrorReset()
+	feature := layer.NextFeature()
+	if feature == nil && C.CPLGetLastErrorType() >= C.CE_Failure {
+		return nil, fmt.Errorf("error: reading layer %q: %s", layer.Name(), C.GoString(C.CPLGetLastErrorMsg()))
+	}
+	return feature, nil
+}
+
+// IteratedFeature is a feature yielded by Layer.Features. It is destroyed
+// once the loop body returns unless Keep is called.
+type IteratedFeature struct {
+	Feature
+	kept bool
+}
+
+// Keep hands ownership of the feature to the caller, who must Destroy it.
+func (feature *IteratedFeature) Keep() Feature {
+	feature.kept = true
+	return feature.Feature
+}
+
+// Features returns an iterator over the features of the layer matching
+// its current spatial and attribute filters. Reading restarts from the
+// first feature. Each feature is destroyed once the loop body returns or
+// panics, unless the body calls Keep on it. A read error is yielded once
+// and ends the iteration.
+func (layer Layer) Features() iter.Seq2[*IteratedFeature, error] {
+	return func(yield func(*IteratedFeature, error) bool) {
+		layer.ResetReading()
+		for {
+			feature, err := layer.nextFeature()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if feature == nil {
+				return
+			}
+			more := func() bool {
+				iterated := &IteratedFeature{Feature: *feature}
+				// Destroy the feature even when the loop body panics.
+				defer func() {
+					if !iterated.kept {
+						iterated.Destroy()
+					}
+				}()
+				return yield(iterated, nil)
+			}()
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// FIDs returns an iterator over the feature IDs of the layer matching its
+// current spatial and attribute filters. Reading restarts from the first
+// feature. A read error is yielded once and ends the iteration.
+func (layer Layer) FIDs() iter.Seq2[int64, error] {
+	return func(yield func(int64, error) bool) {
+		for feature, err := range layer.Features() {
+			if err != nil {
+				yield(0, err)
+				return
+			}
+			if !yield(feature.FID(), nil) {
+				return
+			}
+		}
+	}
+}
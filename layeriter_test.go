@@ -0,0 +1,105 @@
+package gdal
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func createPointLayer(t *testing.T, count int) (DataSource, Layer) {
+	t.Helper()
+
+	ds, layer := createMemoryVectorLayer(t, "points")
+	addLayerField(t, layer, "value", FT_Integer)
+	for i := 0; i < count; i++ {
+		feature := layer.Definition().Create()
+		feature.SetFieldInteger(0, i)
+		point, err := CreateFromWKT(fmt.Sprintf("POINT (%d %d)", i, i), SpatialReference{})
+		if err != nil {
+			t.Fatalf("CreateFromWKT: %v", err)
+		}
+		if err := feature.SetGeometryDirectly(point); err != nil {
+			t.Fatalf("SetGeometryDirectly: %v", err)
+		}
+		if err := layer.Create(feature); err != nil {
+			t.Fatalf("Layer.Create: %v", err)
+		}
+		feature.Destroy()
+	}
+	return ds, layer
+}
+
+func collectValues(t *testing.T, layer Layer) []int {
+	t.Helper()
+
+	var values []int
+	for feature, err := range layer.Features() {
+		if err != nil {
+			t.Fatalf("Features: %v", err)
+		}
+		values = append(values, feature.FieldAsInteger(0))
+	}
+	return values
+}
+
+func TestLayerFeatures(t *testing.T) {
+	ds, layer := createPointLayer(t, 5)
+	defer ds.Destroy()
+
+	if got := collectValues(t, layer); !reflect.DeepEqual(got, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("Features() values = %v", got)
+	}
+
+	var kept []Feature
+	for feature, err := range layer.Features() {
+		if err != nil {
+			t.Fatalf("Features: %v", err)
+		}
+		kept = append(kept, feature.Keep())
+		if len(kept) == 2 {
+			break
+		}
+	}
+	if len(kept) != 2 || kept[0].FieldAsInteger(0) != 0 || kept[1].FieldAsInteger(0) != 1 {
+		t.Errorf("kept %d features", len(kept))
+	}
+	for _, feature := range kept {
+		feature.Destroy()
+	}
+
+	// Iteration restarts after an early break.
+	if got := collectValues(t, layer); len(got) != 5 {
+		t.Errorf("Features() after break returned %d features, want 5", len(got))
+	}
+
+	if err := layer.SetAttributeFilter("value >= 3"); err != nil {
+		t.Fatalf("SetAttributeFilter: %v", err)
+	}
+	if got := collectValues(t, layer); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Errorf("Features() with attribute filter = %v", got)
+	}
+	if err := layer.SetAttributeFilter(""); err != nil {
+		t.Fatalf("SetAttributeFilter: %v", err)
+	}
+
+	layer.SetSpatialFilterRect(0.5, 0.5, 2.5, 2.5)
+	if got := collectValues(t, layer); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Features() with spatial filter = %v", got)
+	}
+}
+
+func TestLayerFIDs(t *testing.T) {
+	ds, layer := createPointLayer(t, 3)
+	defer ds.Destroy()
+
+	var fids []int64
+	for fid, err := range layer.FIDs() {
+		if err != nil {
+			t.Fatalf("FIDs: %v", err)
+		}
+		fids = append(fids, fid)
+	}
+	if len(fids) != 3 || fids[0] == fids[1] {
+		t.Errorf("FIDs() = %v", fids)
+	}
+}
@@ -0,0 +1,152 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+)
+
+// MaskFlags describes the status of a mask band.
+type MaskFlags int
+
+// GMF_ALL_VALID and related constants are exported GDAL/OGR symbols.
+const (
+	GMF_ALL_VALID   = MaskFlags(C.GMF_ALL_VALID)
+	GMF_PER_DATASET = MaskFlags(C.GMF_PER_DATASET)
+	GMF_ALPHA       = MaskFlags(C.GMF_ALPHA)
+	GMF_NODATA      = MaskFlags(C.GMF_NODATA)
+)
+
+// Has reports whether all bits of flag are set.
+func (flags MaskFlags) Has(flag MaskFlags) bool {
+	return flags&flag == flag
+}
+
+// Window is a rectangular region of a raster in pixel coordinates.
+// A window with zero XSize and YSize covers the whole raster.
+type Window struct {
+	XOff, YOff   int
+	XSize, YSize int
+}
+
+// resolve returns the window with a zero size expanded to the band extent
+// and checks that it fits into the band.
+func (window Window) resolve(rasterBand RasterBand) (Window, error) {
+	if window.XSize == 0 && window.YSize == 0 {
+		window.XSize = rasterBand.XSize() - window.XOff
+		window.YSize = rasterBand.YSize() - window.YOff
+	}
+	if window.XOff < 0 || window.YOff < 0 || window.XSize <= 0 || window.YSize <= 0 ||
+		window.XOff+window.XSize > rasterBand.XSize() || window.YOff+window.YSize > rasterBand.YSize() {
+		return window, fmt.Errorf("error: window %+v is outside of the %dx%d raster",
+			window, rasterBand.XSize(), rasterBand.YSize())
+	}
+	return window, nil
+}
+
+// ReadMask reads the mask of the band over window. An element is true when
+// the corresponding pixel is valid.
+func (rasterBand RasterBand) ReadMask(window Window) ([]bool, error) {
+	window, err := window.resolve(rasterBand)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := make([]uint8, window.XSize*window.YSize)
+	err = rasterBand.GetMaskBand().IO(
+		Read,
+		window.XOff, window.YOff, window.XSize, window.YSize,
+		buffer,
+		window.XSize, window.YSize,
+		0, 0,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	mask := make([]bool, len(buffer))
+	for i, value := range buffer {
+		mask[i] = value != 0
+	}
+	return mask, nil
+}
+
+// NoDataToAlpha writes 0 into alpha where the band holds its nodata value
+// and 255 elsewhere.
+func (rasterBand RasterBand) NoDataToAlpha(alpha RasterBand) error {
+	noData, ok := rasterBand.NoDataValue()
+	if !ok {
+		return fmt.Errorf("error: band has no nodata value")
+	}
+	if err := checkSameBandSize(rasterBand, alpha); err != nil {
+		return err
+	}
+
+	xSize, ySize := rasterBand.XSize(), rasterBand.YSize()
+	values := make([]float64, xSize)
+	alphaRow := make([]uint8, xSize)
+	for y := 0; y < ySize; y++ {
+		if err := rasterBand.IO(Read, 0, y, xSize, 1, values, xSize, 1, 0, 0); err != nil {
+			return err
+		}
+		for x, value := range values {
+			if isNoData(value, noData) {
+				alphaRow[x] = 0
+			} else {
+				alphaRow[x] = 255
+			}
+		}
+		if err := alpha.IO(Write, 0, y, xSize, 1, alphaRow, xSize, 1, 0, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AlphaToNoData writes noData into the band wherever alpha is 0 and sets it
+// as the nodata value of the band.
+func (rasterBand RasterBand) AlphaToNoData(alpha RasterBand, noData float64) error {
+	if err := checkSameBandSize(rasterBand, alpha); err != nil {
+		return err
+	}
+
+	xSize, ySize := rasterBand.XSize(), rasterBand.YSize()
+	values := make([]float64, xSize)
+	alphaRow := make([]uint8, xSize)
+	for y := 0; y < ySize; y++ {
+		if err := alpha.IO(Read, 0, y, xSize, 1, alphaRow, xSize, 1, 0, 0); err != nil {
+			return err
+		}
+		if err := rasterBand.IO(Read, 0, y, xSize, 1, values, xSize, 1, 0, 0); err != nil {
+			return err
+		}
+		for x, a := range alphaRow {
+			if a == 0 {
+				values[x] = noData
+			}
+		}
+		if err := rasterBand.IO(Write, 0, y, xSize, 1, values, xSize, 1, 0, 0); err != nil {
+			return err
+		}
+	}
+	return rasterBand.SetNoDataValue(noData)
+}
+
+func checkSameBandSize(a, b RasterBand) error {
+	if a.XSize() != b.XSize() || a.YSize() != b.YSize() {
+		return fmt.Errorf("error: band sizes differ: %dx%d and %dx%d",
+			a.XSize(), a.YSize(), b.XSize(), b.YSize())
+	}
+	return nil
+}
+
+func isNoData(value, noData float64) bool {
+	if math.IsNaN(noData) {
+		return math.IsNaN(value)
+	}
+	return value == noData
+}
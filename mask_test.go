@@ -0,0 +1,105 @@
+package gdal
+
+import (
+	"os"
+	"testing"
+)
+
+func TestReadMaskUsesNoData(t *testing.T) {
+	ds := createMemoryRasterDataset(t, 4, 2, 1, Byte)
+	defer ds.Close()
+
+	band := ds.RasterBand(1)
+	values := []uint8{0, 1, 2, 0, 5, 0, 7, 8}
+	if err := band.IO(Write, 0, 0, 4, 2, values, 4, 2, 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Write): %v", err)
+	}
+	if err := band.SetNoDataValue(0); err != nil {
+		t.Fatalf("SetNoDataValue: %v", err)
+	}
+
+	if flags := band.GetMaskFlags(); !flags.Has(GMF_NODATA) {
+		t.Errorf("GetMaskFlags() = %d, want GMF_NODATA", flags)
+	}
+
+	mask, err := band.ReadMask(Window{})
+	if err != nil {
+		t.Fatalf("ReadMask: %v", err)
+	}
+	for i, value := range values {
+		if mask[i] != (value != 0) {
+			t.Errorf("mask[%d] = %v for value %d", i, mask[i], value)
+		}
+	}
+
+	mask, err = band.ReadMask(Window{XOff: 1, YOff: 1, XSize: 2, YSize: 1})
+	if err != nil {
+		t.Fatalf("ReadMask(window): %v", err)
+	}
+	if len(mask) != 2 || mask[0] || !mask[1] {
+		t.Errorf("ReadMask(window) = %v, want [false true]", mask)
+	}
+
+	if _, err := band.ReadMask(Window{XOff: 3, XSize: 2, YSize: 1}); err == nil {
+		t.Error("expected error for window outside of the raster")
+	}
+}
+
+func TestNoDataAlphaRoundTrip(t *testing.T) {
+	ds := createMemoryRasterDataset(t, 3, 1, 2, Byte)
+	defer ds.Close()
+
+	band, alpha := ds.RasterBand(1), ds.RasterBand(2)
+	if err := band.IO(Write, 0, 0, 3, 1, []uint8{9, 4, 9}, 3, 1, 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Write): %v", err)
+	}
+
+	if err := band.NoDataToAlpha(alpha); err == nil {
+		t.Error("expected error for band without nodata")
+	}
+
+	if err := band.SetNoDataValue(9); err != nil {
+		t.Fatalf("SetNoDataValue: %v", err)
+	}
+	if err := band.NoDataToAlpha(alpha); err != nil {
+		t.Fatalf("NoDataToAlpha: %v", err)
+	}
+	alphaValues := make([]uint8, 3)
+	if err := alpha.IO(Read, 0, 0, 3, 1, alphaValues, 3, 1, 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Read): %v", err)
+	}
+	if alphaValues[0] != 0 || alphaValues[1] != 255 || alphaValues[2] != 0 {
+		t.Errorf("alpha = %v, want [0 255 0]", alphaValues)
+	}
+
+	if err := band.AlphaToNoData(alpha, 200); err != nil {
+		t.Fatalf("AlphaToNoData: %v", err)
+	}
+	values := make([]uint8, 3)
+	if err := band.IO(Read, 0, 0, 3, 1, values, 3, 1, 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Read): %v", err)
+	}
+	if values[0] != 200 || values[1] != 4 || values[2] != 200 {
+		t.Errorf("values = %v, want [200 4 200]", values)
+	}
+	if noData, ok := band.NoDataValue(); !ok || noData != 200 {
+		t.Errorf("NoDataValue() = (%v, %v), want (200, true)", noData, ok)
+	}
+}
+
+func TestDatasetCreateMaskBandDefaultsToPerDatasetForGTiff(t *testing.T) {
+	filename := "./tmp/mask_per_dataset.tif"
+	defer os.Remove(filename)
+
+	ds := createGTiffRasterDataset(t, filename, 8, 8, 2)
+	defer ds.Close()
+
+	if err := ds.CreateMaskBand(0); err != nil {
+		t.Fatalf("CreateMaskBand(0): %v", err)
+	}
+	for band := 1; band <= 2; band++ {
+		if flags := ds.RasterBand(band).GetMaskFlags(); flags != GMF_PER_DATASET {
+			t.Errorf("band %d GetMaskFlags() = %d, want GMF_PER_DATASET", band, flags)
+		}
+	}
+}
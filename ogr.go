@@ -1520,33 +1520,138 @@ func (layer Layer) SetIgnoredFields(names []string) error {
 	return ErrFromOGRErr(C.OGR_L_SetIgnoredFields(layer.cval, (**C.char)(unsafe.Pointer(&cNames[0]))))
 }
 
-// Return the intersection of two layers
-// Unimplemented: Intersection
-// Will be new in 2.0
+// layerOperation identifies one of the OGR_L_* overlay operations.
+type layerOperation int
 
-// Return the union of two layers
-// Unimplemented: Union
-// Will be new in 2.0
+const (
+	layerIntersection layerOperation = iota
+	layerUnion
+	layerSymDifference
+	layerIdentity
+	layerUpdate
+	layerClip
+	layerErase
+)
+
+// overlay runs operation between layer and method, writing into result.
+func (layer Layer) overlay(
+	operation layerOperation,
+	method, result Layer,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	callback := newGoGDALProgressCallback(progress, data)
+	defer callback.close()
+
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+	cOpts := (**C.char)(unsafe.Pointer(&opts[0]))
+
+	var err C.OGRErr
+	switch operation {
+	case layerIntersection:
+		err = C.OGR_L_Intersection(layer.cval, method.cval, result.cval, cOpts, callback.fn, callback.arg)
+	case layerUnion:
+		err = C.OGR_L_Union(layer.cval, method.cval, result.cval, cOpts, callback.fn, callback.arg)
+	case layerSymDifference:
+		err = C.OGR_L_SymDifference(layer.cval, method.cval, result.cval, cOpts, callback.fn, callback.arg)
+	case layerIdentity:
+		err = C.OGR_L_Identity(layer.cval, method.cval, result.cval, cOpts, callback.fn, callback.arg)
+	case layerUpdate:
+		err = C.OGR_L_Update(layer.cval, method.cval, result.cval, cOpts, callback.fn, callback.arg)
+	case layerClip:
+		err = C.OGR_L_Clip(layer.cval, method.cval, result.cval, cOpts, callback.fn, callback.arg)
+	case layerErase:
+		err = C.OGR_L_Erase(layer.cval, method.cval, result.cval, cOpts, callback.fn, callback.arg)
+	}
+	return ErrFromOGRErr(err)
+}
+
+// Intersection writes into result the areas common to features of this
+// layer and of method, with the attributes of both. Options such as
+// SKIP_FAILURES=YES, PROMOTE_TO_MULTI=YES, INPUT_PREFIX and METHOD_PREFIX
+// are described for OGR_L_Intersection. Fields missing from an empty
+// result layer are created.
+func (layer Layer) Intersection(
+	method, result Layer,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return layer.overlay(layerIntersection, method, result, options, progress, data)
+}
 
-// Return the symmetric difference of two layers
-// Unimplemented: SymDifference
-// Will be new in 2.0
+// Union writes into result the union of the features of this layer and of
+// method, split where they overlap, with the attributes of both.
+func (layer Layer) Union(
+	method, result Layer,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return layer.overlay(layerUnion, method, result, options, progress, data)
+}
 
-// Identify features in this layer with ones from the provided layer
-// Unimplemented: Identity
-// Will be new in 2.0
+// SymDifference writes into result the areas of this layer and of method
+// not covered by the other layer.
+func (layer Layer) SymDifference(
+	method, result Layer,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return layer.overlay(layerSymDifference, method, result, options, progress, data)
+}
 
-// Update this layer with features from the provided layer
-// Unimplemented: Update
-// Will be new in 2.0
+// Identity writes into result the features of this layer, split where they
+// overlap features of method and given the attributes of those.
+func (layer Layer) Identity(
+	method, result Layer,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return layer.overlay(layerIdentity, method, result, options, progress, data)
+}
 
-// Clip off areas that are not covered by the provided layer
-// Unimplemented: Clip
-// Will be new in 2.0
+// Update writes into result the features of this layer with the areas
+// covered by method replaced by the features of method.
+func (layer Layer) Update(
+	method, result Layer,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return layer.overlay(layerUpdate, method, result, options, progress, data)
+}
 
-// Remove areas that are covered by the provided layer
-// Unimplemented: Erase
-// Will be new in 2.0
+// Clip writes into result the parts of the features of this layer covered
+// by method, keeping only the attributes of this layer.
+func (layer Layer) Clip(
+	method, result Layer,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return layer.overlay(layerClip, method, result, options, progress, data)
+}
+
+// Erase writes into result the parts of the features of this layer not
+// covered by method.
+func (layer Layer) Erase(
+	method, result Layer,
+	options []string,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	return layer.overlay(layerErase, method, result, options, progress, data)
+}
 
 /* -------------------------------------------------------------------- */
 /*      Data source functions                                           */
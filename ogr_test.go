@@ -0,0 +1,122 @@
+package gdal
+
+import (
+	"math"
+	"testing"
+)
+
+func createSquareLayer(t *testing.T, name, field string, value int, minXY, maxXY float64) (DataSource, Layer) {
+	t.Helper()
+
+	ds, layer := createMemoryVectorLayer(t, name)
+	addLayerField(t, layer, field, FT_Integer)
+
+	feature := layer.Definition().Create()
+	defer feature.Destroy()
+	feature.SetFieldInteger(0, value)
+	square, err := CreateFromWKT(polygonWKT(minXY, maxXY), SpatialReference{})
+	if err != nil {
+		t.Fatalf("CreateFromWKT: %v", err)
+	}
+	if err := feature.SetGeometryDirectly(square); err != nil {
+		t.Fatalf("SetGeometryDirectly: %v", err)
+	}
+	if err := layer.Create(feature); err != nil {
+		t.Fatalf("Layer.Create: %v", err)
+	}
+	return ds, layer
+}
+
+func polygonWKT(min, max float64) string {
+	return "POLYGON ((" +
+		formatFloat(min) + " " + formatFloat(min) + "," +
+		formatFloat(max) + " " + formatFloat(min) + "," +
+		formatFloat(max) + " " + formatFloat(max) + "," +
+		formatFloat(min) + " " + formatFloat(max) + "," +
+		formatFloat(min) + " " + formatFloat(min) + "))"
+}
+
+// overlayResult summarizes the features of a result layer.
+func overlayResult(t *testing.T, layer Layer) (count, fields int, area float64) {
+	t.Helper()
+
+	fields = layer.Definition().FieldCount()
+	for feature, err := range layer.Features() {
+		if err != nil {
+			t.Fatalf("Features: %v", err)
+		}
+		count++
+		area += feature.Geometry().Area()
+	}
+	return count, fields, area
+}
+
+func TestLayerOverlayOperations(t *testing.T) {
+	inputDS, input := createSquareLayer(t, "input", "a", 1, 0, 2)
+	defer inputDS.Destroy()
+	methodDS, method := createSquareLayer(t, "method", "b", 2, 1, 3)
+	defer methodDS.Destroy()
+
+	for _, test := range []struct {
+		name   string
+		run    func(Layer, Layer, Layer, []string, ProgressFunc, interface{}) error
+		count  int
+		fields int
+		area   float64
+	}{
+		{"Intersection", input.Intersection, 1, 2, 1},
+		{"Union", input.Union, 3, 2, 7},
+		{"SymDifference", input.SymDifference, 2, 2, 6},
+		{"Identity", input.Identity, 2, 2, 4},
+		{"Update", input.Update, 2, 1, 7},
+		{"Clip", input.Clip, 1, 1, 1},
+		{"Erase", input.Erase, 1, 1, 3},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			resultDS, result := createMemoryVectorLayer(t, "result")
+			defer resultDS.Destroy()
+
+			var called bool
+			progress := func(complete float64, message string, data interface{}) int {
+				called = true
+				return 1
+			}
+			if err := test.run(method, result, nil, progress, nil); err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			count, fields, area := overlayResult(t, result)
+			if count != test.count || fields != test.fields || math.Abs(area-test.area) > 1e-9 {
+				t.Errorf("%s: %d features, %d fields, area %v; want %d, %d, %v",
+					test.name, count, fields, area, test.count, test.fields, test.area)
+			}
+			if !called {
+				t.Errorf("%s: progress callback not called", test.name)
+			}
+		})
+	}
+}
+
+func TestLayerIntersectionOptions(t *testing.T) {
+	inputDS, input := createSquareLayer(t, "input", "v", 1, 0, 2)
+	defer inputDS.Destroy()
+	methodDS, method := createSquareLayer(t, "method", "v", 2, 1, 3)
+	defer methodDS.Destroy()
+	resultDS, result := createMemoryVectorLayer(t, "result")
+	defer resultDS.Destroy()
+
+	options := []string{"INPUT_PREFIX=in_", "METHOD_PREFIX=m_"}
+	if err := input.Intersection(method, result, options, nil, nil); err != nil {
+		t.Fatalf("Intersection: %v", err)
+	}
+	definition := result.Definition()
+	if definition.FieldIndex("in_v") < 0 || definition.FieldIndex("m_v") < 0 {
+		t.Errorf("prefixed fields missing, have %d fields", definition.FieldCount())
+	}
+
+	cancel := func(complete float64, message string, data interface{}) int { return 0 }
+	resultDS2, result2 := createMemoryVectorLayer(t, "cancelled")
+	defer resultDS2.Destroy()
+	if err := input.Intersection(method, result2, nil, cancel, nil); err == nil {
+		t.Error("Intersection ignored a cancelling progress callback")
+	}
+}
@@ -0,0 +1,133 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_gdal_options.h"
+#include <cpl_string.h>
+*/
+import "C"
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// OptionDescriptor describes a creation, open or layer creation option
+// advertised by a driver.
+type OptionDescriptor struct {
+	Name string
+	// Type is the option type as reported by GDAL, e.g. "int", "float",
+	// "boolean", "string" or "string-select".
+	Type        string
+	Description string
+	Default     string
+	// Values lists the allowed values of a string-select option.
+	Values []string
+	// Min and Max are the bounds of a numeric option, when given.
+	Min, Max string
+	// Scope limits the option to "raster" or "vector" datasets, when given.
+	Scope string
+}
+
+type optionListXML struct {
+	Options []struct {
+		Name        string `xml:"name,attr"`
+		Type        string `xml:"type,attr"`
+		Description string `xml:"description,attr"`
+		Default     string `xml:"default,attr"`
+		Min         string `xml:"min,attr"`
+		Max         string `xml:"max,attr"`
+		Scope       string `xml:"scope,attr"`
+		Values      []struct {
+			Value string `xml:",chardata"`
+		} `xml:"Value"`
+	} `xml:"Option"`
+}
+
+// ParseOptionList parses an option list XML document such as the value of
+// DMD_CREATIONOPTIONLIST.
+func ParseOptionList(text string) ([]OptionDescriptor, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	var list optionListXML
+	if err := xml.Unmarshal([]byte(text), &list); err != nil {
+		return nil, fmt.Errorf("option list parse error: %v", err)
+	}
+
+	options := make([]OptionDescriptor, 0, len(list.Options))
+	for _, option := range list.Options {
+		descriptor := OptionDescriptor{
+			Name:        option.Name,
+			Type:        option.Type,
+			Description: option.Description,
+			Default:     option.Default,
+			Min:         option.Min,
+			Max:         option.Max,
+			Scope:       option.Scope,
+		}
+		for _, value := range option.Values {
+			descriptor.Values = append(descriptor.Values, strings.TrimSpace(value.Value))
+		}
+		options = append(options, descriptor)
+	}
+	return options, nil
+}
+
+// CreationOptions returns the dataset creation options of the driver.
+func (driver Driver) CreationOptions() ([]OptionDescriptor, error) {
+	return ParseOptionList(driver.MetadataItem(DMD_CREATIONOPTIONLIST, ""))
+}
+
+// OpenOptions returns the dataset open options of the driver.
+func (driver Driver) OpenOptions() ([]OptionDescriptor, error) {
+	return ParseOptionList(driver.MetadataItem(DMD_OPENOPTIONLIST, ""))
+}
+
+// LayerCreationOptions returns the vector layer creation options of the driver.
+func (driver Driver) LayerCreationOptions() ([]OptionDescriptor, error) {
+	return ParseOptionList(driver.MetadataItem(DS_LAYER_CREATIONOPTIONLIST, ""))
+}
+
+// OptionValidationError lists the problems GDAL found in an option list.
+type OptionValidationError struct {
+	Driver   string
+	Messages []string
+}
+
+// Error implements the error interface.
+func (err *OptionValidationError) Error() string {
+	if len(err.Messages) == 0 {
+		return fmt.Sprintf("invalid %s creation options", err.Driver)
+	}
+	return fmt.Sprintf("invalid %s creation options: %s", err.Driver, strings.Join(err.Messages, "; "))
+}
+
+// ValidateCreationOptions checks options against the creation option list of
+// the driver. It returns an *OptionValidationError describing every problem
+// GDAL reports, such as unknown options or values out of range.
+func (driver Driver) ValidateCreationOptions(options []string) error {
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	var cMessages **C.char
+	valid := C.go_ValidateCreationOptions(
+		driver.cval,
+		(**C.char)(unsafe.Pointer(&opts[0])),
+		&cMessages,
+	)
+	messages := cStringListToSlice(cMessages)
+	C.CSLDestroy(cMessages)
+
+	if valid != 0 && len(messages) == 0 {
+		return nil
+	}
+	return &OptionValidationError{Driver: driver.ShortName(), Messages: messages}
+}
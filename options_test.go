@@ -0,0 +1,92 @@
+package gdal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseOptionList(t *testing.T) {
+	options, err := ParseOptionList(`<CreationOptionList>
+   <Option name='COMPRESS' type='string-select' default='NONE' description='Compression'>
+       <Value>NONE</Value>
+       <Value alias='DEFLATE'>ZIP</Value>
+   </Option>
+   <Option name='JPEG_QUALITY' type='int' min='1' max='100' default='75'/>
+</CreationOptionList>`)
+	if err != nil {
+		t.Fatalf("ParseOptionList: %v", err)
+	}
+	if len(options) != 2 {
+		t.Fatalf("got %d options, want 2", len(options))
+	}
+	compress := options[0]
+	if compress.Name != "COMPRESS" || compress.Type != "string-select" || compress.Default != "NONE" ||
+		len(compress.Values) != 2 || compress.Values[1] != "ZIP" {
+		t.Errorf("options[0] = %+v", compress)
+	}
+	if quality := options[1]; quality.Min != "1" || quality.Max != "100" {
+		t.Errorf("options[1] = %+v", quality)
+	}
+
+	if options, err := ParseOptionList(""); err != nil || options != nil {
+		t.Errorf("ParseOptionList(\"\") = (%v, %v), want (nil, nil)", options, err)
+	}
+	if _, err := ParseOptionList("<broken"); err == nil {
+		t.Error("expected error for malformed XML")
+	}
+}
+
+func TestDriverOptionLists(t *testing.T) {
+	gtiff, err := GetDriverByName(DriverNameGTiff)
+	if err != nil {
+		t.Fatalf("GetDriverByName(GTiff): %v", err)
+	}
+	options, err := gtiff.CreationOptions()
+	if err != nil {
+		t.Fatalf("CreationOptions: %v", err)
+	}
+	found := false
+	for _, option := range options {
+		if option.Name == "COMPRESS" {
+			found = len(option.Values) > 0
+		}
+	}
+	if !found {
+		t.Error("GTiff creation options do not describe COMPRESS values")
+	}
+
+	openOptions, err := gtiff.OpenOptions()
+	if err != nil || len(openOptions) == 0 {
+		t.Errorf("OpenOptions() = (%d options, %v)", len(openOptions), err)
+	}
+
+	gpkg, err := GetDriverByName(DriverNameGPKG)
+	if err != nil {
+		t.Fatalf("GetDriverByName(GPKG): %v", err)
+	}
+	layerOptions, err := gpkg.LayerCreationOptions()
+	if err != nil || len(layerOptions) == 0 {
+		t.Errorf("LayerCreationOptions() = (%d options, %v)", len(layerOptions), err)
+	}
+}
+
+func TestValidateCreationOptions(t *testing.T) {
+	driver, err := GetDriverByName(DriverNameGTiff)
+	if err != nil {
+		t.Fatalf("GetDriverByName(GTiff): %v", err)
+	}
+
+	if err := driver.ValidateCreationOptions([]string{"COMPRESS=DEFLATE", "TILED=YES"}); err != nil {
+		t.Errorf("ValidateCreationOptions(valid): %v", err)
+	}
+
+	err = driver.ValidateCreationOptions([]string{"COMPRESS=BOGUS"})
+	var validationErr *OptionValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("ValidateCreationOptions(invalid) error = %v, want *OptionValidationError", err)
+	}
+	if len(validationErr.Messages) == 0 || !strings.Contains(err.Error(), "BOGUS") {
+		t.Errorf("error = %q, want a message mentioning BOGUS", err)
+	}
+}
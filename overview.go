@@ -0,0 +1,247 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"unsafe"
+)
+
+// OverviewLocation selects where BuildOverviewsWithOptions stores overviews.
+type OverviewLocation int
+
+const (
+	// OverviewLocationAuto lets the driver decide. GTiff datasets opened in
+	// update mode get internal overviews, read-only ones an external .ovr file.
+	OverviewLocationAuto OverviewLocation = iota
+	// OverviewLocationInternal stores overviews inside the dataset itself.
+	// The dataset must be opened in update mode.
+	OverviewLocationInternal
+	// OverviewLocationExternal stores overviews in a sidecar .ovr file.
+	OverviewLocationExternal
+)
+
+// OverviewOptions describes an overview build.
+type OverviewOptions struct {
+	// Resampling is the default resampling method, e.g. "NEAREST" or "AVERAGE".
+	Resampling string
+	// Levels lists the decimation factors to build, e.g. 2, 4, 8.
+	Levels []int
+	// Bands lists the 1-based bands to process. All bands are used when empty.
+	Bands []int
+	// BandResampling overrides Resampling for individual 1-based bands.
+	BandResampling map[int]string
+	// Location selects between internal and external overviews.
+	Location OverviewLocation
+}
+
+// BuildOverviewsWithOptions builds overviews for the bands and levels given in
+// opts. When all bands share one method, they are built in a single pass.
+// Otherwise, since drivers such as GTiff create overview levels for all bands
+// at once, missing levels are first created empty and each selected band is
+// then computed with its own method. Progress is reported over all steps.
+func (dataset Dataset) BuildOverviewsWithOptions(
+	opts OverviewOptions,
+	progress ProgressFunc,
+	data interface{},
+) error {
+	if len(opts.Levels) == 0 {
+		return fmt.Errorf("error: at least one overview level is required")
+	}
+	for _, level := range opts.Levels {
+		if level < 2 {
+			return fmt.Errorf("error: overview level %d must be at least 2", level)
+		}
+	}
+
+	bands := opts.Bands
+	if len(bands) == 0 {
+		bands = make([]int, dataset.RasterCount())
+		for i := range bands {
+			bands[i] = i + 1
+		}
+	}
+	for _, band := range bands {
+		if band < 1 || band > dataset.RasterCount() {
+			return fmt.Errorf("error: band %d is out of range", band)
+		}
+	}
+
+	resampling := opts.Resampling
+	if resampling == "" {
+		resampling = "NEAREST"
+	}
+
+	methods := make(map[int]string, len(bands))
+	single := len(bands) == dataset.RasterCount()
+	for _, band := range bands {
+		methods[band] = resampling
+		if method := opts.BandResampling[band]; method != "" && method != resampling {
+			methods[band] = method
+			single = false
+		}
+	}
+	bands = append([]int(nil), bands...)
+	sort.Ints(bands)
+
+	switch opts.Location {
+	case OverviewLocationInternal:
+		if dataset.Access() != Update {
+			return fmt.Errorf("error: internal overviews require a dataset opened in update mode")
+		}
+	case OverviewLocationExternal:
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer setThreadLocalConfigOption("TIFF_USE_OVR", "YES")()
+	}
+
+	if single {
+		return dataset.BuildOverviews(
+			resampling,
+			len(opts.Levels), opts.Levels,
+			len(bands), bands,
+			progress,
+			data,
+		)
+	}
+
+	if missing := dataset.missingOverviewLevels(opts.Levels); len(missing) > 0 {
+		all := make([]int, dataset.RasterCount())
+		for i := range all {
+			all[i] = i + 1
+		}
+		// NONE creates the levels without computing them.
+		if err := dataset.BuildOverviews("NONE", len(missing), missing, len(all), all, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	steps := float64(len(bands))
+	for i, band := range bands {
+		rasterBand := dataset.RasterBand(band)
+		overviews, err := rasterBand.overviewsForLevels(opts.Levels)
+		if err != nil {
+			return err
+		}
+		err = rasterBand.RegenerateOverviews(
+			overviews,
+			methods[band],
+			subProgress(progress, float64(i)/steps, float64(i+1)/steps),
+			data,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// missingOverviewLevels returns the levels some band of the dataset has no
+// overview for.
+func (dataset Dataset) missingOverviewLevels(levels []int) []int {
+	var missing []int
+	for _, level := range levels {
+		for band := 1; band <= dataset.RasterCount(); band++ {
+			if _, err := dataset.RasterBand(band).overviewsForLevels([]int{level}); err != nil {
+				missing = append(missing, level)
+				break
+			}
+		}
+	}
+	return missing
+}
+
+// Overviews returns all overview bands of the raster band.
+func (rasterBand RasterBand) Overviews() []RasterBand {
+	count := rasterBand.OverviewCount()
+	overviews := make([]RasterBand, 0, count)
+	for i := 0; i < count; i++ {
+		overviews = append(overviews, rasterBand.Overview(i))
+	}
+	return overviews
+}
+
+// BestOverviewForResolution returns the most reduced overview whose pixel size
+// does not exceed resolution, given in georeferenced units of the owning
+// dataset, along with its index. The band itself and -1 are returned when no
+// overview qualifies.
+func (rasterBand RasterBand) BestOverviewForResolution(resolution float64) (RasterBand, int) {
+	baseResolution := 1.0
+	if dataset := rasterBand.GetDataset(); dataset.cval != nil {
+		gt := dataset.GeoTransform()
+		baseResolution = math.Hypot(gt[1], gt[4])
+	}
+
+	best, bestIndex, bestResolution := rasterBand, -1, baseResolution
+	for i, overview := range rasterBand.Overviews() {
+		if overview.XSize() == 0 {
+			continue
+		}
+		overviewResolution := baseResolution * float64(rasterBand.XSize()) / float64(overview.XSize())
+		if overviewResolution <= resolution*(1+1e-9) && overviewResolution > bestResolution {
+			best, bestIndex, bestResolution = overview, i, overviewResolution
+		}
+	}
+	return best, bestIndex
+}
+
+// overviewsForLevels returns the overview bands matching the decimation
+// factors in levels, in the same order.
+func (rasterBand RasterBand) overviewsForLevels(levels []int) ([]RasterBand, error) {
+	overviews := rasterBand.Overviews()
+	result := make([]RasterBand, 0, len(levels))
+	for _, level := range levels {
+		xSize := (rasterBand.XSize() + level - 1) / level
+		found := false
+		for _, overview := range overviews {
+			if overview.XSize() == xSize {
+				result = append(result, overview)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("error: no overview found for level %d", level)
+		}
+	}
+	return result, nil
+}
+
+// subProgress maps the progress of one step onto [start, end] of the overall
+// progress reported to progress.
+func subProgress(progress ProgressFunc, start, end float64) ProgressFunc {
+	if progress == nil {
+		return nil
+	}
+	return func(complete float64, message string, data interface{}) int {
+		return progress(start+(end-start)*complete, message, data)
+	}
+}
+
+// setThreadLocalConfigOption sets a configuration option for the current
+// thread and returns a function restoring the previous value.
+func setThreadLocalConfigOption(key, value string) func() {
+	cKey := C.CString(key)
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+
+	var previous *C.char
+	if old := C.CPLGetThreadLocalConfigOption(cKey, nil); old != nil {
+		previous = C.CString(C.GoString(old))
+	}
+	C.CPLSetThreadLocalConfigOption(cKey, cValue)
+
+	return func() {
+		C.CPLSetThreadLocalConfigOption(cKey, previous)
+		C.free(unsafe.Pointer(cKey))
+		if previous != nil {
+			C.free(unsafe.Pointer(previous))
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package gdal
+
+import (
+	"os"
+	"testing"
+)
+
+func createGTiffRasterDataset(t *testing.T, filename string, xSize, ySize, bands int) Dataset {
+	t.Helper()
+
+	driver, err := GetDriverByName("GTiff")
+	if err != nil {
+		t.Fatalf("GetDriverByName(GTiff): %v", err)
+	}
+
+	ds := driver.Create(filename, xSize, ySize, bands, Byte, nil)
+	if err := ds.SetGeoTransform([6]float64{0, 10, 0, 0, 0, -10}); err != nil {
+		ds.Close()
+		t.Fatalf("SetGeoTransform: %v", err)
+	}
+
+	buffer := make([]uint8, xSize*ySize)
+	for i := range buffer {
+		buffer[i] = uint8(i % 251)
+	}
+	for band := 1; band <= bands; band++ {
+		if err := ds.RasterBand(band).IO(Write, 0, 0, xSize, ySize, buffer, xSize, ySize, 0, 0); err != nil {
+			ds.Close()
+			t.Fatalf("RasterBand.IO(Write): %v", err)
+		}
+	}
+	ds.FlushCache()
+
+	return ds
+}
+
+func TestBuildOverviewsWithOptionsInternalAndClear(t *testing.T) {
+	filename := "./tmp/overviews_internal.tif"
+	defer os.Remove(filename)
+
+	ds := createGTiffRasterDataset(t, filename, 64, 64, 2)
+	defer ds.Close()
+
+	var last float64
+	progress := func(complete float64, message string, data interface{}) int {
+		if complete+1e-9 < last {
+			t.Errorf("progress went backwards: %f after %f", complete, last)
+		}
+		last = complete
+		return 1
+	}
+
+	err := ds.BuildOverviewsWithOptions(OverviewOptions{
+		Resampling:     "NEAREST",
+		Levels:         []int{2, 4},
+		BandResampling: map[int]string{2: "AVERAGE"},
+		Location:       OverviewLocationInternal,
+	}, progress, nil)
+	if err != nil {
+		t.Fatalf("BuildOverviewsWithOptions: %v", err)
+	}
+	if last < 0.99 {
+		t.Errorf("final progress = %f, want 1", last)
+	}
+
+	band := ds.RasterBand(1)
+	if got := band.OverviewCount(); got != 2 {
+		t.Fatalf("OverviewCount() = %d, want 2", got)
+	}
+
+	overview, index := band.BestOverviewForResolution(25)
+	if index != 0 || overview.XSize() != 32 {
+		t.Errorf("BestOverviewForResolution(25) = (%d px, %d), want (32 px, 0)", overview.XSize(), index)
+	}
+	if _, index := band.BestOverviewForResolution(5); index != -1 {
+		t.Errorf("BestOverviewForResolution(5) index = %d, want -1", index)
+	}
+	if got := band.SampleOverview(16 * 16).XSize(); got != 16 {
+		t.Errorf("SampleOverview(256).XSize() = %d, want 16", got)
+	}
+
+	if err := ds.ClearOverviews(); err != nil {
+		t.Fatalf("ClearOverviews: %v", err)
+	}
+	if got := band.OverviewCount(); got != 0 {
+		t.Errorf("OverviewCount() after clear = %d, want 0", got)
+	}
+}
+
+func TestBuildOverviewsWithOptionsExternal(t *testing.T) {
+	filename := "./tmp/overviews_external.tif"
+	defer os.Remove(filename)
+	defer os.Remove(filename + ".ovr")
+
+	ds := createGTiffRasterDataset(t, filename, 32, 32, 1)
+	defer ds.Close()
+
+	err := ds.BuildOverviewsWithOptions(OverviewOptions{
+		Levels:   []int{2},
+		Location: OverviewLocationExternal,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("BuildOverviewsWithOptions: %v", err)
+	}
+	if _, err := os.Stat(filename + ".ovr"); err != nil {
+		t.Errorf("external overview file missing: %v", err)
+	}
+}
+
+func TestBuildOverviewsWithOptionsBandSubset(t *testing.T) {
+	filename := "./tmp/overviews_subset.tif"
+	defer os.Remove(filename)
+
+	ds := createGTiffRasterDataset(t, filename, 64, 64, 2)
+	defer ds.Close()
+
+	err := ds.BuildOverviewsWithOptions(OverviewOptions{
+		Resampling: "AVERAGE",
+		Levels:     []int{2},
+		Bands:      []int{2},
+		Location:   OverviewLocationInternal,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("BuildOverviewsWithOptions: %v", err)
+	}
+
+	sums := make([]int, 2)
+	for band := 1; band <= 2; band++ {
+		rasterBand := ds.RasterBand(band)
+		if got := rasterBand.OverviewCount(); got != 1 {
+			t.Fatalf("band %d OverviewCount() = %d, want 1", band, got)
+		}
+		buffer := make([]uint8, 32*32)
+		if err := rasterBand.Overview(0).IO(Read, 0, 0, 32, 32, buffer, 32, 32, 0, 0); err != nil {
+			t.Fatalf("band %d Overview(0).IO(Read): %v", band, err)
+		}
+		for _, value := range buffer {
+			sums[band-1] += int(value)
+		}
+	}
+	if sums[0] != 0 {
+		t.Errorf("band 1 overview sum = %d, want 0 for a band outside the subset", sums[0])
+	}
+	if sums[1] == 0 {
+		t.Error("band 2 overview was not computed")
+	}
+}
+
+func TestBuildOverviewsWithOptionsRejectsInvalidInput(t *testing.T) {
+	ds := createMemoryRasterDataset(t, 8, 8, 1, Byte)
+	defer ds.Close()
+
+	if err := ds.BuildOverviewsWithOptions(OverviewOptions{}, nil, nil); err == nil {
+		t.Error("expected error for empty levels")
+	}
+	if err := ds.BuildOverviewsWithOptions(OverviewOptions{Levels: []int{2}, Bands: []int{3}}, nil, nil); err == nil {
+		t.Error("expected error for out of range band")
+	}
+}
+
+func TestRegenerateOverviewsRejectsEmptyOverviews(t *testing.T) {
+	ds := createMemoryRasterDataset(t, 8, 8, 1, Byte)
+	defer ds.Close()
+
+	if err := ds.RasterBand(1).RegenerateOverviews(nil, "AVERAGE", nil, nil); err == nil {
+		t.Error("expected error for empty overviews")
+	}
+}
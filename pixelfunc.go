@@ -0,0 +1,180 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_gdal_pixfunc.h"
+*/
+import "C"
+import (
+	"fmt"
+	"runtime/cgo"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+const pixelFuncHandleArg = "go_pixel_func_handle"
+
+// PixelFuncArgs holds the inputs of a PixelFunc call.
+type PixelFuncArgs struct {
+	// Sources holds one buffer per VRT source converted to float64.
+	Sources [][]float64
+	// SourceType is the data type the sources were read with.
+	SourceType DataType
+	// XSize and YSize are the dimensions of the requested buffer.
+	XSize, YSize int
+	// Arguments holds the PixelFunctionArguments of the VRT band together
+	// with the NoData builtin argument when the band has a nodata value.
+	Arguments map[string]string
+}
+
+// PixelFunc computes the values of a VRT derived band. dst has XSize*YSize
+// elements in row-major order and is converted to the band buffer type.
+type PixelFunc func(args PixelFuncArgs, dst []float64) error
+
+var pixelFuncs = struct {
+	sync.Mutex
+	handles map[string]cgo.Handle
+}{handles: map[string]cgo.Handle{}}
+
+// RegisterPixelFunc registers fn under name so that VRT derived bands with
+// <PixelFunctionType>name</PixelFunctionType> call it at read time.
+// arguments declares the optional PixelFunctionArguments fn reads.
+// Registering a name again replaces the previous function.
+func RegisterPixelFunc(name string, fn PixelFunc, arguments ...string) error {
+	if name == "" {
+		return fmt.Errorf("pixel function name must not be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("pixel function %q must not be nil", name)
+	}
+
+	pixelFuncs.Lock()
+	defer pixelFuncs.Unlock()
+
+	handle := cgo.NewHandle(fn)
+
+	var metadata strings.Builder
+	metadata.WriteString("<PixelFunctionArgumentsList>")
+	fmt.Fprintf(&metadata, "<Argument type='constant' name='%s' value='%d'/>", pixelFuncHandleArg, uintptr(handle))
+	metadata.WriteString("<Argument type='builtin' value='NoData'/>")
+	for _, argument := range arguments {
+		fmt.Fprintf(&metadata, "<Argument type='string' name='%s' optional='true'/>", xmlAttrEscape(argument))
+	}
+	metadata.WriteString("</PixelFunctionArgumentsList>")
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cMetadata := C.CString(metadata.String())
+	defer C.free(unsafe.Pointer(cMetadata))
+
+	switch C.go_AddPixelFunc(cName, cMetadata) {
+	case 0:
+	case -1:
+		handle.Delete()
+		return fmt.Errorf("pixel function %q registration requires GDAL 3.4 or newer", name)
+	default:
+		handle.Delete()
+		return fmt.Errorf("pixel function %q registration error", name)
+	}
+
+	if previous, ok := pixelFuncs.handles[name]; ok {
+		previous.Delete()
+	}
+	pixelFuncs.handles[name] = handle
+	return nil
+}
+
+func xmlAttrEscape(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "'", "&apos;", "\"", "&quot;").Replace(value)
+}
+
+//export goGDALPixelFuncProxyA
+func goGDALPixelFuncProxyA(
+	handle C.uintptr_t,
+	sources *unsafe.Pointer,
+	nSources C.int,
+	data unsafe.Pointer,
+	xSize, ySize C.int,
+	srcType, bufType C.int,
+	pixelSpace, lineSpace C.int,
+	args **C.char,
+) (result C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			reportPixelFuncError(fmt.Errorf("pixel function panic: %v", r))
+			result = C.int(C.CE_Failure)
+		}
+	}()
+
+	fn, ok := cgo.Handle(handle).Value().(PixelFunc)
+	if !ok {
+		reportPixelFuncError(fmt.Errorf("pixel function handle is invalid"))
+		return C.int(C.CE_Failure)
+	}
+
+	width, height := int(xSize), int(ySize)
+	count := width * height
+	sourceType := DataType(srcType)
+
+	pixelArgs := PixelFuncArgs{
+		Sources:    make([][]float64, int(nSources)),
+		SourceType: sourceType,
+		XSize:      width,
+		YSize:      height,
+		Arguments:  map[string]string{},
+	}
+	for key, value := range parseNameValueList(args) {
+		if key != pixelFuncHandleArg {
+			pixelArgs.Arguments[key] = value
+		}
+	}
+
+	var cSources []unsafe.Pointer
+	if nSources > 0 {
+		cSources = unsafe.Slice(sources, int(nSources))
+	}
+	for i, source := range cSources {
+		buffer := make([]float64, count)
+		if count > 0 {
+			C.GDALCopyWords64(
+				source, C.GDALDataType(srcType), C.int(sourceType.Size()/8),
+				unsafe.Pointer(&buffer[0]), C.GDT_Float64, 8,
+				C.GPtrDiff_t(count),
+			)
+		}
+		pixelArgs.Sources[i] = buffer
+	}
+
+	dst := make([]float64, count)
+	if err := fn(pixelArgs, dst); err != nil {
+		reportPixelFuncError(err)
+		return C.int(C.CE_Failure)
+	}
+
+	for y := 0; y < height && width > 0; y++ {
+		C.GDALCopyWords64(
+			unsafe.Pointer(&dst[y*width]), C.GDT_Float64, 8,
+			unsafe.Add(data, y*int(lineSpace)), C.GDALDataType(bufType), pixelSpace,
+			C.GPtrDiff_t(width),
+		)
+	}
+	return C.int(C.CE_None)
+}
+
+func reportPixelFuncError(err error) {
+	cMessage := C.CString(err.Error())
+	defer C.free(unsafe.Pointer(cMessage))
+	C.go_PixelFuncError(cMessage)
+}
+
+// parseNameValueList converts a NULL terminated list of KEY=VALUE strings.
+func parseNameValueList(list **C.char) map[string]string {
+	result := map[string]string{}
+	for _, item := range cStringListToSlice(list) {
+		if parts := strings.SplitN(item, "=", 2); len(parts) == 2 {
+			result[parts[0]] = parts[1]
+		}
+	}
+	return result
+}
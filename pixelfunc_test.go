@@ -0,0 +1,99 @@
+package gdal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func openDerivedVRT(t *testing.T, source, pixelFunction, arguments string) Dataset {
+	t.Helper()
+
+	xml := fmt.Sprintf(`<VRTDataset rasterXSize="4" rasterYSize="2">
+  <VRTRasterBand dataType="Float32" band="1" subClass="VRTDerivedRasterBand">
+    <PixelFunctionType>%s</PixelFunctionType>
+    %s
+    <SimpleSource>
+      <SourceFilename relativeToVRT="0">%s</SourceFilename>
+      <SourceBand>1</SourceBand>
+    </SimpleSource>
+    <SimpleSource>
+      <SourceFilename relativeToVRT="0">%s</SourceFilename>
+      <SourceBand>2</SourceBand>
+    </SimpleSource>
+  </VRTRasterBand>
+</VRTDataset>`, pixelFunction, arguments, source, source)
+
+	ds, err := Open(xml, ReadOnly)
+	if err != nil {
+		t.Fatalf("Open(VRT): %v", err)
+	}
+	return ds
+}
+
+func TestRegisterPixelFunc(t *testing.T) {
+	filename := "./tmp/pixelfunc_source.tif"
+	defer os.Remove(filename)
+
+	src := createGTiffRasterDataset(t, filename, 4, 2, 2)
+	src.Close()
+
+	err := RegisterPixelFunc("go_test_sum_offset", func(args PixelFuncArgs, dst []float64) error {
+		if len(args.Sources) != 2 {
+			return fmt.Errorf("got %d sources", len(args.Sources))
+		}
+		offset, _ := strconv.ParseFloat(args.Arguments["offset"], 64)
+		for i := range dst {
+			dst[i] = args.Sources[0][i] + args.Sources[1][i] + offset
+		}
+		return nil
+	}, "offset")
+	if err != nil {
+		t.Fatalf("RegisterPixelFunc: %v", err)
+	}
+
+	ds := openDerivedVRT(t, filename, "go_test_sum_offset", `<PixelFunctionArguments offset="0.5"/>`)
+	defer ds.Close()
+
+	values := make([]float32, 8)
+	if err := ds.RasterBand(1).IO(Read, 0, 0, 4, 2, values, 4, 2, 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Read): %v", err)
+	}
+	for i, value := range values {
+		if want := float32(2*i) + 0.5; value != want {
+			t.Errorf("pixel %d = %v, want %v", i, value, want)
+		}
+	}
+}
+
+func TestRegisterPixelFuncReportsErrors(t *testing.T) {
+	filename := "./tmp/pixelfunc_error_source.tif"
+	defer os.Remove(filename)
+
+	src := createGTiffRasterDataset(t, filename, 4, 2, 2)
+	src.Close()
+
+	err := RegisterPixelFunc("go_test_fail", func(PixelFuncArgs, []float64) error {
+		return errors.New("boom")
+	})
+	if err != nil {
+		t.Fatalf("RegisterPixelFunc: %v", err)
+	}
+
+	ds := openDerivedVRT(t, filename, "go_test_fail", "")
+	defer ds.Close()
+
+	values := make([]float32, 8)
+	if err := ds.RasterBand(1).IO(Read, 0, 0, 4, 2, values, 4, 2, 0, 0); err == nil {
+		t.Error("expected read error from failing pixel function")
+	}
+
+	if err := RegisterPixelFunc("", func(PixelFuncArgs, []float64) error { return nil }); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if err := RegisterPixelFunc("go_test_nil", nil); err == nil {
+		t.Error("expected error for nil function")
+	}
+}
@@ -0,0 +1,288 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// RATTableType is an exported GDAL/OGR type.
+type RATTableType int
+
+// GRTT_THEMATIC and related constants are exported GDAL/OGR symbols.
+const (
+	GRTT_THEMATIC  = RATTableType(C.GRTT_THEMATIC)
+	GRTT_ATHEMATIC = RATTableType(C.GRTT_ATHEMATIC)
+)
+
+// TableType returns whether the table is thematic or athematic.
+func (rat RasterAttributeTable) TableType() RATTableType {
+	return RATTableType(C.GDALRATGetTableType(rat.cval))
+}
+
+// SetTableType sets whether the table is thematic or athematic.
+func (rat RasterAttributeTable) SetTableType(tableType RATTableType) error {
+	return ErrFromCPLErr(C.GDALRATSetTableType(rat.cval, C.GDALRATTableType(tableType)))
+}
+
+// RemoveStatistics removes statistics columns such as histograms from the table.
+func (rat RasterAttributeTable) RemoveStatistics() {
+	C.GDALRATRemoveStatistics(rat.cval)
+}
+
+// ChangesAreWrittenToFile reports whether changes to the table are written
+// directly to the file rather than on SetDefaultRAT.
+func (rat RasterAttributeTable) ChangesAreWrittenToFile() bool {
+	return C.GDALRATChangesAreWrittenToFile(rat.cval) != 0
+}
+
+// ReadColumn reads len(buffer) values of column field starting at startRow.
+// buffer must be a []float64, []int32, []int or []string.
+func (rat RasterAttributeTable) ReadColumn(field, startRow int, buffer interface{}) error {
+	return rat.columnIO(Read, field, startRow, buffer)
+}
+
+// WriteColumn writes the values of buffer into column field starting at
+// startRow. buffer must be a []float64, []int32, []int or []string.
+func (rat RasterAttributeTable) WriteColumn(field, startRow int, buffer interface{}) error {
+	return rat.columnIO(Write, field, startRow, buffer)
+}
+
+func (rat RasterAttributeTable) columnIO(rwFlag RWFlag, field, startRow int, buffer interface{}) error {
+	if field < 0 || field >= rat.ColumnCount() {
+		return fmt.Errorf("error: field %d is out of range", field)
+	}
+
+	switch data := buffer.(type) {
+	case []float64:
+		if len(data) == 0 {
+			return nil
+		}
+		return ErrFromCPLErr(C.GDALRATValuesIOAsDouble(
+			rat.cval, C.GDALRWFlag(rwFlag), C.int(field), C.int(startRow), C.int(len(data)),
+			(*C.double)(unsafe.Pointer(&data[0])),
+		))
+	case []int32:
+		if len(data) == 0 {
+			return nil
+		}
+		return ErrFromCPLErr(C.GDALRATValuesIOAsInteger(
+			rat.cval, C.GDALRWFlag(rwFlag), C.int(field), C.int(startRow), C.int(len(data)),
+			(*C.int)(unsafe.Pointer(&data[0])),
+		))
+	case []int:
+		if len(data) == 0 {
+			return nil
+		}
+		values := IntSliceToCInt(data)
+		err := ErrFromCPLErr(C.GDALRATValuesIOAsInteger(
+			rat.cval, C.GDALRWFlag(rwFlag), C.int(field), C.int(startRow), C.int(len(values)),
+			cIntSlicePtr(values),
+		))
+		if err == nil && rwFlag == Read {
+			copy(data, CIntSliceToInt(values))
+		}
+		return err
+	case []string:
+		if len(data) == 0 {
+			return nil
+		}
+		values := make([]*C.char, len(data))
+		if rwFlag == Write {
+			for i, value := range data {
+				values[i] = C.CString(value)
+				defer C.free(unsafe.Pointer(values[i]))
+			}
+		}
+		err := ErrFromCPLErr(C.GDALRATValuesIOAsString(
+			rat.cval, C.GDALRWFlag(rwFlag), C.int(field), C.int(startRow), C.int(len(values)),
+			(**C.char)(unsafe.Pointer(&values[0])),
+		))
+		if rwFlag == Read {
+			for i, value := range values {
+				if err == nil {
+					data[i] = C.GoString(value)
+				}
+				C.CPLFree(unsafe.Pointer(value))
+			}
+		}
+		return err
+	}
+	return fmt.Errorf("error: unsupported column buffer type %T", buffer)
+}
+
+// ratJSON mirrors the layout of GDALRATSerializeJSON.
+type ratJSON struct {
+	Row0Min   *float64          `json:"row0Min,omitempty"`
+	BinSize   *float64          `json:"binSize,omitempty"`
+	TableType string            `json:"tableType"`
+	FieldDefn []ratFieldDefJSON `json:"fieldDefn"`
+	Row       []ratRowJSON      `json:"row"`
+}
+
+type ratFieldDefJSON struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Type  int    `json:"type"`
+	Usage int    `json:"usage"`
+}
+
+type ratRowJSON struct {
+	Index int           `json:"index"`
+	F     []interface{} `json:"f"`
+}
+
+// SerializeJSON returns the table as JSON in the layout used by GDAL.
+func (rat RasterAttributeTable) SerializeJSON() (string, error) {
+	doc := ratJSON{TableType: "thematic", FieldDefn: []ratFieldDefJSON{}, Row: []ratRowJSON{}}
+	if rat.TableType() == GRTT_ATHEMATIC {
+		doc.TableType = "athematic"
+	}
+	if row0Min, binSize, ok := rat.LinearBinning(); ok {
+		doc.Row0Min, doc.BinSize = &row0Min, &binSize
+	}
+
+	columns := rat.ColumnCount()
+	rows := rat.RowCount()
+	values := make([][]interface{}, rows)
+	for row := range values {
+		values[row] = make([]interface{}, columns)
+	}
+	for col := 0; col < columns; col++ {
+		colType := rat.TypeOfCol(col)
+		doc.FieldDefn = append(doc.FieldDefn, ratFieldDefJSON{
+			Index: col,
+			Name:  rat.NameOfCol(col),
+			Type:  int(colType),
+			Usage: int(rat.UsageOfCol(col)),
+		})
+		if rows == 0 {
+			continue
+		}
+		switch colType {
+		case GFT_Integer:
+			buffer := make([]int, rows)
+			if err := rat.ReadColumn(col, 0, buffer); err != nil {
+				return "", err
+			}
+			for row, value := range buffer {
+				values[row][col] = value
+			}
+		case GFT_Real:
+			buffer := make([]float64, rows)
+			if err := rat.ReadColumn(col, 0, buffer); err != nil {
+				return "", err
+			}
+			for row, value := range buffer {
+				values[row][col] = value
+			}
+		default:
+			buffer := make([]string, rows)
+			if err := rat.ReadColumn(col, 0, buffer); err != nil {
+				return "", err
+			}
+			for row, value := range buffer {
+				values[row][col] = value
+			}
+		}
+	}
+	for row, f := range values {
+		doc.Row = append(doc.Row, ratRowJSON{Index: row, F: f})
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// Unmarshal reads all rows into dst, which must be a pointer to a slice of
+// structs. Fields are matched to columns by a `rat:"name"` tag or by field
+// name; fields tagged `rat:"-"` are skipped. A tagged field without a
+// matching column is an error, as is a value that does not fit an integer
+// field.
+func (rat RasterAttributeTable) Unmarshal(dst interface{}) error {
+	sliceValue := reflect.ValueOf(dst)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("error: destination must be a pointer to a slice, got %T", dst)
+	}
+	sliceValue = sliceValue.Elem()
+	elemType := sliceValue.Type().Elem()
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("error: destination elements must be structs, got %s", elemType)
+	}
+
+	columns := map[string]int{}
+	for col := 0; col < rat.ColumnCount(); col++ {
+		columns[rat.NameOfCol(col)] = col
+	}
+
+	rows := rat.RowCount()
+	result := reflect.MakeSlice(sliceValue.Type(), rows, rows)
+
+	for i := 0; i < elemType.NumField(); i++ {
+		field := elemType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, tagged := field.Tag.Lookup("rat")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		col, ok := columns[name]
+		if !ok {
+			if tagged {
+				return fmt.Errorf("error: column %q not found", name)
+			}
+			continue
+		}
+		if rows == 0 {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			buffer := make([]int, rows)
+			if err := rat.ReadColumn(col, 0, buffer); err != nil {
+				return err
+			}
+			for row, value := range buffer {
+				if err := setInt(result.Index(row).Field(i), int64(value)); err != nil {
+					return fmt.Errorf("error: field %s, row %d: %v", field.Name, row, err)
+				}
+			}
+		case reflect.Float32, reflect.Float64:
+			buffer := make([]float64, rows)
+			if err := rat.ReadColumn(col, 0, buffer); err != nil {
+				return err
+			}
+			for row, value := range buffer {
+				result.Index(row).Field(i).SetFloat(value)
+			}
+		case reflect.String:
+			buffer := make([]string, rows)
+			if err := rat.ReadColumn(col, 0, buffer); err != nil {
+				return err
+			}
+			for row, value := range buffer {
+				result.Index(row).Field(i).SetString(value)
+			}
+		default:
+			return fmt.Errorf("error: field %s has unsupported type %s", field.Name, field.Type)
+		}
+	}
+
+	sliceValue.Set(result)
+	return nil
+}
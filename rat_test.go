@@ -0,0 +1,157 @@
+package gdal
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func createClassRAT(t *testing.T) RasterAttributeTable {
+	t.Helper()
+
+	rat := CreateRasterAttributeTable()
+	for _, column := range []struct {
+		name  string
+		typ   RATFieldType
+		usage RATFieldUsage
+	}{
+		{"Value", GFT_Integer, GFU_MinMax},
+		{"Area", GFT_Real, GFU_Generic},
+		{"Class", GFT_String, GFU_Name},
+	} {
+		if err := rat.CreateColumn(column.name, column.typ, column.usage); err != nil {
+			rat.Destroy()
+			t.Fatalf("CreateColumn(%s): %v", column.name, err)
+		}
+	}
+	rat.SetRowCount(3)
+
+	if err := rat.WriteColumn(0, 0, []int{1, 2, 3}); err != nil {
+		t.Fatalf("WriteColumn(int): %v", err)
+	}
+	if err := rat.WriteColumn(1, 0, []float64{1.5, 2.5, 3.5}); err != nil {
+		t.Fatalf("WriteColumn(float64): %v", err)
+	}
+	if err := rat.WriteColumn(2, 0, []string{"water", "forest", "urban"}); err != nil {
+		t.Fatalf("WriteColumn(string): %v", err)
+	}
+	return rat
+}
+
+func TestRATColumnIO(t *testing.T) {
+	rat := createClassRAT(t)
+	defer rat.Destroy()
+
+	ints := make([]int32, 2)
+	if err := rat.ReadColumn(0, 1, ints); err != nil {
+		t.Fatalf("ReadColumn(int32): %v", err)
+	}
+	if ints[0] != 2 || ints[1] != 3 {
+		t.Errorf("ReadColumn(int32) = %v, want [2 3]", ints)
+	}
+
+	floats := make([]float64, 3)
+	if err := rat.ReadColumn(1, 0, floats); err != nil {
+		t.Fatalf("ReadColumn(float64): %v", err)
+	}
+	if floats[2] != 3.5 {
+		t.Errorf("ReadColumn(float64) = %v", floats)
+	}
+
+	strs := make([]string, 3)
+	if err := rat.ReadColumn(2, 0, strs); err != nil {
+		t.Fatalf("ReadColumn(string): %v", err)
+	}
+	if strs[1] != "forest" || rat.ValueAsString(0, 2) != "water" {
+		t.Errorf("ReadColumn(string) = %v", strs)
+	}
+
+	if err := rat.ReadColumn(5, 0, floats); err == nil {
+		t.Error("expected error for out of range column")
+	}
+	if err := rat.ReadColumn(0, 0, []byte{0}); err == nil {
+		t.Error("expected error for unsupported buffer")
+	}
+}
+
+func TestRATTableTypeAndJSON(t *testing.T) {
+	rat := createClassRAT(t)
+	defer rat.Destroy()
+
+	if err := rat.SetTableType(GRTT_ATHEMATIC); err != nil {
+		t.Fatalf("SetTableType: %v", err)
+	}
+	if got := rat.TableType(); got != GRTT_ATHEMATIC {
+		t.Errorf("TableType() = %d, want GRTT_ATHEMATIC", got)
+	}
+	if rat.ChangesAreWrittenToFile() {
+		t.Error("ChangesAreWrittenToFile() = true for an in-memory table")
+	}
+
+	text, err := rat.SerializeJSON()
+	if err != nil {
+		t.Fatalf("SerializeJSON: %v", err)
+	}
+	var doc struct {
+		TableType string `json:"tableType"`
+		FieldDefn []struct {
+			Name string `json:"name"`
+		} `json:"fieldDefn"`
+		Row []struct {
+			F []interface{} `json:"f"`
+		} `json:"row"`
+	}
+	if err := json.Unmarshal([]byte(text), &doc); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if doc.TableType != "athematic" || len(doc.FieldDefn) != 3 || len(doc.Row) != 3 {
+		t.Fatalf("SerializeJSON() = %s", text)
+	}
+	if doc.Row[1].F[2] != "forest" || doc.Row[1].F[0] != float64(2) {
+		t.Errorf("row 1 = %v", doc.Row[1].F)
+	}
+}
+
+func TestRATUnmarshal(t *testing.T) {
+	rat := createClassRAT(t)
+	defer rat.Destroy()
+
+	type class struct {
+		Value   uint8
+		Area    float32 `rat:"Area"`
+		Name    string  `rat:"Class"`
+		Ignored string  `rat:"-"`
+		Missing int
+	}
+
+	var rows []class
+	if err := rat.Unmarshal(&rows); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Unmarshal() returned %d rows, want 3", len(rows))
+	}
+	if rows[2] != (class{Value: 3, Area: 3.5, Name: "urban"}) {
+		t.Errorf("rows[2] = %+v", rows[2])
+	}
+
+	var bad []struct {
+		X int `rat:"Nope"`
+	}
+	if err := rat.Unmarshal(&bad); err == nil {
+		t.Error("expected error for missing tagged column")
+	}
+	if err := rat.Unmarshal(rows); err == nil {
+		t.Error("expected error for non-pointer destination")
+	}
+
+	rat.SetValueAsInt(1, 0, -1)
+	var unsigned []struct{ Value uint }
+	if err := rat.Unmarshal(&unsigned); err == nil {
+		t.Error("expected error for a negative value in an unsigned field")
+	}
+	rat.SetValueAsInt(1, 0, 300)
+	var small []struct{ Value int8 }
+	if err := rat.Unmarshal(&small); err == nil {
+		t.Error("expected error for a value overflowing int8")
+	}
+}
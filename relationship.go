@@ -0,0 +1,258 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_gdal_relationship.h"
+#include <cpl_string.h>
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// RelationshipCardinality is the cardinality of a relationship.
+type RelationshipCardinality int
+
+// GRC_OneToOne and related constants mirror GDALRelationshipCardinality.
+const (
+	GRC_OneToOne RelationshipCardinality = iota
+	GRC_OneToMany
+	GRC_ManyToOne
+	GRC_ManyToMany
+)
+
+// RelationshipType is the kind of a relationship.
+type RelationshipType int
+
+// GRT_Composite and related constants mirror GDALRelationshipType.
+const (
+	GRT_Composite RelationshipType = iota
+	GRT_Association
+	GRT_Aggregation
+)
+
+// GDsCAddRelationship and related constants are dataset capabilities for
+// Dataset.TestCapability.
+const (
+	GDsCAddRelationship    = "AddRelationship"
+	GDsCDeleteRelationship = "DeleteRelationship"
+	GDsCUpdateRelationship = "UpdateRelationship"
+)
+
+// Relationship describes a link between the records of two tables of a
+// dataset, through MappingTable for many-to-many relationships.
+type Relationship struct {
+	Name         string
+	LeftTable    string
+	RightTable   string
+	MappingTable string
+	Cardinality  RelationshipCardinality
+	Type         RelationshipType
+	// LeftTableFields and RightTableFields are the key fields of the
+	// related tables.
+	LeftTableFields  []string
+	RightTableFields []string
+	// LeftMappingTableFields and RightMappingTableFields are the fields of
+	// the mapping table matching the left and right keys.
+	LeftMappingTableFields  []string
+	RightMappingTableFields []string
+	ForwardPathLabel        string
+	BackwardPathLabel       string
+	// RelatedTableType is the type of the right table, such as "features"
+	// or "media".
+	RelatedTableType string
+}
+
+// cStringList copies values to a C string list, to be freed with
+// CSLDestroy.
+func cStringList(values []string) **C.char {
+	var list **C.char
+	for _, value := range values {
+		cValue := C.CString(value)
+		list = C.CSLAddString(list, cValue)
+		C.free(unsafe.Pointer(cValue))
+	}
+	return list
+}
+
+// toC converts the relationship, to be freed with freeRelationship.
+func (rel Relationship) toC() C.goRelationship {
+	return C.goRelationship{
+		pszName:                      C.CString(rel.Name),
+		pszLeftTableName:             C.CString(rel.LeftTable),
+		pszRightTableName:            C.CString(rel.RightTable),
+		pszMappingTableName:          C.CString(rel.MappingTable),
+		nCardinality:                 C.int(rel.Cardinality),
+		nType:                        C.int(rel.Type),
+		papszLeftTableFields:         cStringList(rel.LeftTableFields),
+		papszRightTableFields:        cStringList(rel.RightTableFields),
+		papszLeftMappingTableFields:  cStringList(rel.LeftMappingTableFields),
+		papszRightMappingTableFields: cStringList(rel.RightMappingTableFields),
+		pszForwardPathLabel:          C.CString(rel.ForwardPathLabel),
+		pszBackwardPathLabel:         C.CString(rel.BackwardPathLabel),
+		pszRelatedTableType:          C.CString(rel.RelatedTableType),
+	}
+}
+
+// freeRelationship frees the members of a relationship built by toC. Its
+// strings come from C.CString and must not be released with CPLFree.
+func freeRelationship(cRel *C.goRelationship) {
+	for _, value := range []*C.char{
+		cRel.pszName,
+		cRel.pszLeftTableName,
+		cRel.pszRightTableName,
+		cRel.pszMappingTableName,
+		cRel.pszForwardPathLabel,
+		cRel.pszBackwardPathLabel,
+		cRel.pszRelatedTableType,
+	} {
+		C.free(unsafe.Pointer(value))
+	}
+	for _, list := range []**C.char{
+		cRel.papszLeftTableFields,
+		cRel.papszRightTableFields,
+		cRel.papszLeftMappingTableFields,
+		cRel.papszRightMappingTableFields,
+	} {
+		C.CSLDestroy(list)
+	}
+}
+
+func relationshipFromC(cRel *C.goRelationship) Relationship {
+	return Relationship{
+		Name:                    C.GoString(cRel.pszName),
+		LeftTable:               C.GoString(cRel.pszLeftTableName),
+		RightTable:              C.GoString(cRel.pszRightTableName),
+		MappingTable:            C.GoString(cRel.pszMappingTableName),
+		Cardinality:             RelationshipCardinality(cRel.nCardinality),
+		Type:                    RelationshipType(cRel.nType),
+		LeftTableFields:         cStringListToSlice(cRel.papszLeftTableFields),
+		RightTableFields:        cStringListToSlice(cRel.papszRightTableFields),
+		LeftMappingTableFields:  cStringListToSlice(cRel.papszLeftMappingTableFields),
+		RightMappingTableFields: cStringListToSlice(cRel.papszRightMappingTableFields),
+		ForwardPathLabel:        C.GoString(cRel.pszForwardPathLabel),
+		BackwardPathLabel:       C.GoString(cRel.pszBackwardPathLabel),
+		RelatedTableType:        C.GoString(cRel.pszRelatedTableType),
+	}
+}
+
+// RelationshipNames returns the names of the relationships of the dataset.
+// Requires GDAL 3.6 or newer.
+func (dataset Dataset) RelationshipNames() ([]string, error) {
+	var names **C.char
+	if C.go_GDALDatasetGetRelationshipNames(dataset.cval, &names) == -1 {
+		return nil, fmt.Errorf("error: relationships require GDAL 3.6 or newer")
+	}
+	defer C.CSLDestroy(names)
+	return cStringListToSlice(names), nil
+}
+
+// Relationship returns the named relationship of the dataset, and false
+// when there is none. Requires GDAL 3.6 or newer.
+func (dataset Dataset) Relationship(name string) (Relationship, bool, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var cRel C.goRelationship
+	switch C.go_GDALDatasetGetRelationship(dataset.cval, cName, &cRel) {
+	case 1:
+		defer C.go_FreeRelationship(&cRel)
+		return relationshipFromC(&cRel), true, nil
+	case -1:
+		return Relationship{}, false, fmt.Errorf("error: relationships require GDAL 3.6 or newer")
+	default:
+		return Relationship{}, false, nil
+	}
+}
+
+// Relationships returns all relationships of the dataset. Requires GDAL 3.6
+// or newer.
+func (dataset Dataset) Relationships() ([]Relationship, error) {
+	names, err := dataset.RelationshipNames()
+	if err != nil {
+		return nil, err
+	}
+	relationships := make([]Relationship, 0, len(names))
+	for _, name := range names {
+		rel, ok, err := dataset.Relationship(name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			relationships = append(relationships, rel)
+		}
+	}
+	return relationships, nil
+}
+
+// relationshipResult converts the outcome of a relationship change.
+func relationshipResult(result C.int, reason *C.char, operation, name string) error {
+	message := goStringAndCPLFree(reason)
+	switch result {
+	case 1:
+		return nil
+	case -1:
+		return fmt.Errorf("error: relationships require GDAL 3.6 or newer")
+	default:
+		return fmt.Errorf("error: %s relationship %q: %s", operation, name, message)
+	}
+}
+
+// AddRelationship adds the relationship to the dataset. Drivers may create
+// the mapping table and adjust the name; see RelationshipNames.
+// Requires GDAL 3.6 or newer.
+func (dataset Dataset) AddRelationship(rel Relationship) error {
+	cRel := rel.toC()
+	defer freeRelationship(&cRel)
+
+	var reason *C.char
+	result := C.go_GDALDatasetAddRelationship(dataset.cval, &cRel, &reason)
+	return relationshipResult(result, reason, "adding", rel.Name)
+}
+
+// UpdateRelationship replaces the relationship of the same name. Requires
+// GDAL 3.6 or newer.
+func (dataset Dataset) UpdateRelationship(rel Relationship) error {
+	cRel := rel.toC()
+	defer freeRelationship(&cRel)
+
+	var reason *C.char
+	result := C.go_GDALDatasetUpdateRelationship(dataset.cval, &cRel, &reason)
+	return relationshipResult(result, reason, "updating", rel.Name)
+}
+
+// DeleteRelationship deletes the named relationship. Requires GDAL 3.6 or
+// newer.
+func (dataset Dataset) DeleteRelationship(name string) error {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var reason *C.char
+	result := C.go_GDALDatasetDeleteRelationship(dataset.cval, cName, &reason)
+	return relationshipResult(result, reason, "deleting", name)
+}
+
+// CopyRelationships adds to dst the relationships of src whose tables exist
+// in dst, for instance after copying layers with VectorTranslate. Failures
+// are joined and do not stop the copy. Requires GDAL 3.6 or newer.
+func CopyRelationships(dst, src Dataset) error {
+	relationships, err := src.Relationships()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, rel := range relationships {
+		if dst.LayerByName(rel.LeftTable).cval == nil || dst.LayerByName(rel.RightTable).cval == nil {
+			continue
+		}
+		if _, ok, _ := dst.Relationship(rel.Name); ok {
+			continue
+		}
+		if err := dst.AddRelationship(rel); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
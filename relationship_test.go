@@ -0,0 +1,103 @@
+package gdal
+
+import (
+	"os"
+	"testing"
+)
+
+func createRelationshipGPKG(t *testing.T, filename string, tables ...string) Dataset {
+	t.Helper()
+
+	os.Remove(filename)
+	driver, err := GetDriverByName(DriverNameGPKG)
+	if err != nil {
+		t.Fatalf("GetDriverByName(GPKG): %v", err)
+	}
+	ds := driver.Create(filename, 0, 0, 0, Unknown, nil)
+	if ds.cval == nil {
+		t.Fatal("GPKG Create returned nil dataset")
+	}
+	for _, table := range tables {
+		if _, err := ds.CreateLayer(table, SpatialReference{}, GT_Point, nil); err != nil {
+			ds.Close()
+			t.Fatalf("CreateLayer(%s): %v", table, err)
+		}
+	}
+	return ds
+}
+
+func TestDatasetRelationships(t *testing.T) {
+	filename := "./tmp/relationship.gpkg"
+	defer os.Remove(filename)
+	ds := createRelationshipGPKG(t, filename, "parcels", "owners")
+	defer ds.Close()
+
+	rel := Relationship{
+		Name:                    "parcel_owners",
+		LeftTable:               "parcels",
+		RightTable:              "owners",
+		MappingTable:            "parcel_owners",
+		Cardinality:             GRC_ManyToMany,
+		Type:                    GRT_Association,
+		LeftTableFields:         []string{"fid"},
+		RightTableFields:        []string{"fid"},
+		LeftMappingTableFields:  []string{"base_id"},
+		RightMappingTableFields: []string{"related_id"},
+		RelatedTableType:        "features",
+	}
+
+	err := ds.AddRelationship(rel)
+	if VERSION_NUM < 3060000 {
+		if err == nil {
+			t.Error("AddRelationship succeeded before GDAL 3.6")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("AddRelationship: %v", err)
+	}
+
+	relationships, err := ds.Relationships()
+	if err != nil {
+		t.Fatalf("Relationships: %v", err)
+	}
+	if len(relationships) != 1 {
+		t.Fatalf("Relationships = %+v, want one", relationships)
+	}
+	got := relationships[0]
+	if got.LeftTable != "parcels" || got.RightTable != "owners" || got.Cardinality != GRC_ManyToMany {
+		t.Errorf("relationship = %+v", got)
+	}
+	if got.MappingTable != "parcel_owners" || ds.LayerByName("parcel_owners").cval == nil {
+		t.Errorf("mapping table %q not created", got.MappingTable)
+	}
+	if len(got.LeftTableFields) != 1 || got.LeftTableFields[0] != "fid" {
+		t.Errorf("LeftTableFields = %v, want [fid]", got.LeftTableFields)
+	}
+
+	if _, ok, err := ds.Relationship("missing"); ok || err != nil {
+		t.Errorf("Relationship(missing) = %v, %v", ok, err)
+	}
+
+	// Copy the related tables and their relationship to another file.
+	copyName := "./tmp/relationship_copy.gpkg"
+	defer os.Remove(copyName)
+	dst := createRelationshipGPKG(t, copyName, "parcels", "owners")
+	defer dst.Close()
+	if err := CopyRelationships(dst, ds); err != nil {
+		t.Fatalf("CopyRelationships: %v", err)
+	}
+	if names, err := dst.RelationshipNames(); err != nil || len(names) != 1 {
+		t.Errorf("copied RelationshipNames = %v, %v", names, err)
+	}
+
+	if err := ds.DeleteRelationship(got.Name); err != nil {
+		t.Fatalf("DeleteRelationship: %v", err)
+	}
+	if names, err := ds.RelationshipNames(); err != nil || len(names) != 0 {
+		t.Errorf("RelationshipNames after delete = %v, %v", names, err)
+	}
+	if err := ds.DeleteRelationship(got.Name); err == nil {
+		t.Error("deleting a missing relationship succeeded")
+	}
+}
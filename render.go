@@ -0,0 +1,403 @@
+package gdal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrEmptyTile is returned by RenderTile when the tile holds no valid pixel.
+var ErrEmptyTile = errors.New("empty tile")
+
+// RenderScale maps source values in [Min, Max] to the 0-255 range.
+type RenderScale struct {
+	Min, Max float64
+}
+
+// RenderOptions describes how RenderTile renders a tile.
+type RenderOptions struct {
+	// TileMatrixSet defaults to WebMercatorQuad.
+	TileMatrixSet TileMatrixSet
+	// Format defaults to TileFormatPNG.
+	Format TileFormat
+	// Quality is the JPEG or WEBP quality, between 1 and 100.
+	Quality int
+	// Resampling is the resampling method used to warp the tile.
+	Resampling ResampleAlg
+	// Bands lists the 1 or 3 bands to render. By default the first three
+	// bands are rendered when the dataset has at least three color bands, the
+	// first band otherwise.
+	Bands []int
+	// Scale stretches source values to 0-255. When nil, Byte bands are
+	// rendered as is and other bands are stretched between the minimum and
+	// maximum of their existing statistics. RenderTile never computes
+	// statistics and fails when there are none; NewTileServer computes an
+	// approximate range once when needed.
+	Scale *RenderScale
+	// ColorMap colors a single band. Percent stops are resolved against Scale,
+	// or against the band range described above.
+	ColorMap *ColorMap
+
+	// ranges caches the band ranges computed by NewTileServer.
+	ranges map[int]RenderScale
+}
+
+func (opts RenderOptions) withDefaults() RenderOptions {
+	if opts.TileMatrixSet.Identifier == "" {
+		opts.TileMatrixSet = WebMercatorQuad
+	}
+	if opts.Format == "" {
+		opts.Format = TileFormatPNG
+	}
+	return opts
+}
+
+// RenderTile renders the tile z/x/y of ds, with rows counted from the top.
+// Only the tile bounds are warped, to a MEM dataset in the SRS of the tile
+// matrix set with an alpha band that makes pixels outside of ds or on nodata
+// transparent; gdalwarp picks overviews when available. The rendered bands
+// are then rescaled or colored and encoded through an in-memory file.
+// ErrEmptyTile is returned when the tile does not hold any valid pixel.
+func RenderTile(ds Dataset, z, x, y int, opts RenderOptions) ([]byte, error) {
+	opts = opts.withDefaults()
+	tms := opts.TileMatrixSet
+
+	outside := z < 0 || z > maxTileZoom
+	if !outside {
+		width, height := tms.MatrixSize(z)
+		outside = x < 0 || x >= width || y < 0 || y >= height
+	}
+	if outside {
+		return nil, fmt.Errorf("error: tile %d/%d/%d is outside of %s", z, x, y, tms.Identifier)
+	}
+	if ds.Projection() == "" {
+		return nil, fmt.Errorf("error: dataset has no spatial reference")
+	}
+
+	bands, err := renderBands(ds, opts)
+	if err != nil {
+		return nil, err
+	}
+	resampling, err := resampleAlgName(opts.Resampling)
+	if err != nil {
+		return nil, err
+	}
+
+	warped, err := warpTile(ds, tms, z, x, y, resampling)
+	if err != nil {
+		return nil, err
+	}
+	defer warped.Close()
+
+	// The alpha band added by the warp comes after the color bands.
+	size := tms.TileSize
+	alpha := warped.RasterCount()
+	mask := make([]uint8, size*size)
+	if err := warped.RasterBand(alpha).IO(Read, 0, 0, size, size, mask, size, size, 0, 0); err != nil {
+		return nil, err
+	}
+	empty := true
+	for _, value := range mask {
+		if value != 0 {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return nil, ErrEmptyTile
+	}
+
+	values := make([][]float64, len(bands))
+	for i, band := range bands {
+		if band >= alpha {
+			return nil, fmt.Errorf("error: band %d cannot be rendered", band)
+		}
+		values[i] = make([]float64, size*size)
+		if err := warped.RasterBand(band).IO(Read, 0, 0, size, size, values[i], size, size, 0, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	colors := len(bands)
+	if opts.ColorMap != nil {
+		colors = 3
+	}
+	output := make([][]uint8, colors+1)
+	for i := range output {
+		output[i] = make([]uint8, size*size)
+	}
+
+	if opts.ColorMap != nil {
+		min, max, err := renderRange(ds, bands[0], opts)
+		if err != nil {
+			return nil, err
+		}
+		stops := opts.ColorMap.resolve(min, max)
+		for i, value := range values[0] {
+			if mask[i] == 0 {
+				continue
+			}
+			c := colorAt(stops, value)
+			output[0][i], output[1][i], output[2][i] = c.R, c.G, c.B
+			output[3][i] = uint8(int(c.A) * int(mask[i]) / 255)
+		}
+	} else {
+		for b, band := range bands {
+			min, max, err := renderRange(ds, band, opts)
+			if err != nil {
+				return nil, err
+			}
+			for i, value := range values[b] {
+				output[b][i] = scaleToByte(value, min, max)
+			}
+		}
+		copy(output[colors], mask)
+	}
+
+	driver, err := GetDriverByName(DriverNameMEM)
+	if err != nil {
+		return nil, err
+	}
+	tile := driver.Create("", size, size, colors+1, Byte, nil)
+	if tile.cval == nil {
+		return nil, fmt.Errorf("error: failed to create tile dataset")
+	}
+	defer tile.Close()
+	for i, buffer := range output {
+		if err := tile.RasterBand(i+1).IO(Write, 0, 0, size, size, buffer, size, size, 0, 0); err != nil {
+			return nil, err
+		}
+	}
+	if err := tile.RasterBand(colors + 1).SetColorInterp(CI_AlphaBand); err != nil {
+		return nil, err
+	}
+
+	return encodeTile(tile, TileOptions{Format: opts.Format, Quality: opts.Quality})
+}
+
+// resampleAlgName returns the gdalwarp name of resampling.
+func resampleAlgName(resampling ResampleAlg) (string, error) {
+	switch resampling {
+	case GRA_NearestNeighbour:
+		return "near", nil
+	case GRA_Bilinear:
+		return "bilinear", nil
+	case GRA_Cubic:
+		return "cubic", nil
+	case GRA_CubicSpline:
+		return "cubicspline", nil
+	case GRA_Lanczos:
+		return "lanczos", nil
+	}
+	return "", fmt.Errorf("error: resampling %d is not supported", resampling)
+}
+
+// renderBands returns the bands of ds rendered with opts.
+func renderBands(ds Dataset, opts RenderOptions) ([]int, error) {
+	count := ds.RasterCount()
+	if count > 0 && ds.RasterBand(count).ColorInterp() == CI_AlphaBand {
+		count--
+	}
+	bands := opts.Bands
+	if len(bands) == 0 {
+		bands = []int{1}
+		if count >= 3 && opts.ColorMap == nil {
+			bands = []int{1, 2, 3}
+		}
+	}
+	if len(bands) != 1 && len(bands) != 3 {
+		return nil, fmt.Errorf("error: 1 or 3 bands must be rendered, got %d", len(bands))
+	}
+	if opts.ColorMap != nil && len(bands) != 1 {
+		return nil, fmt.Errorf("error: a color map needs a single band")
+	}
+	for _, band := range bands {
+		if band < 1 || band > ds.RasterCount() {
+			return nil, fmt.Errorf("error: band %d is out of range", band)
+		}
+	}
+	return bands, nil
+}
+
+// renderNeedsRange reports whether rendering band of ds maps its values
+// through a range: percent color stops or a band other than Byte.
+func renderNeedsRange(ds Dataset, band int, opts RenderOptions) bool {
+	if opts.ColorMap != nil {
+		for _, stop := range opts.ColorMap.Stops {
+			if stop.Percent {
+				return true
+			}
+		}
+		return false
+	}
+	return band > ds.RasterCount() || ds.RasterBand(band).RasterDataType() != Byte
+}
+
+// renderRange returns the source range mapped to colors for band of ds:
+// Scale when given, 0-255 when no range is needed, the range cached by
+// NewTileServer or the existing statistics of the band. Statistics are not
+// computed here, since that would scan the source for every tile and may
+// store them next to the caller's dataset.
+func renderRange(ds Dataset, band int, opts RenderOptions) (float64, float64, error) {
+	if opts.Scale != nil {
+		return opts.Scale.Min, opts.Scale.Max, nil
+	}
+	if !renderNeedsRange(ds, band, opts) {
+		return 0, 255, nil
+	}
+	if scale, ok := opts.ranges[band]; ok {
+		return scale.Min, scale.Max, nil
+	}
+	if band <= ds.RasterCount() {
+		if stats, err := ds.RasterBand(band).GetStatistics(1, 0); err == nil {
+			return stats.Min, stats.Max, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("error: band %d has no statistics, set Scale to render it", band)
+}
+
+// renderRanges returns the ranges of the bands of ds rendered with opts that
+// need one, from their existing statistics or an approximate minimum and
+// maximum, which is not stored on the dataset.
+func renderRanges(ds Dataset, opts RenderOptions) (map[int]RenderScale, error) {
+	bands, err := renderBands(ds, opts)
+	if err != nil || opts.Scale != nil {
+		return nil, err
+	}
+	ranges := map[int]RenderScale{}
+	for _, band := range bands {
+		if !renderNeedsRange(ds, band, opts) {
+			continue
+		}
+		rasterBand := ds.RasterBand(band)
+		if stats, err := rasterBand.GetStatistics(1, 0); err == nil {
+			ranges[band] = RenderScale{Min: stats.Min, Max: stats.Max}
+			continue
+		}
+		min, max := rasterBand.ComputeMinMax(1)
+		ranges[band] = RenderScale{Min: min, Max: max}
+	}
+	return ranges, nil
+}
+
+// scaleToByte maps value from [min, max] to 0-255.
+func scaleToByte(value, min, max float64) uint8 {
+	if max <= min {
+		return 0
+	}
+	scaled := math.Round((value - min) / (max - min) * 255)
+	return uint8(math.Max(0, math.Min(255, scaled)))
+}
+
+// TileServer is an http.Handler serving /{z}/{x}/{y}.{ext} tiles rendered
+// with RenderTile from a pool of datasets opened on the same file.
+type TileServer struct {
+	opts RenderOptions
+	pool chan Dataset
+	size int
+}
+
+// NewTileServer opens poolSize datasets on filename, each serving one
+// request at a time. Band ranges needed without opts.Scale are computed once
+// here.
+func NewTileServer(filename string, poolSize int, opts RenderOptions) (*TileServer, error) {
+	if poolSize < 1 {
+		return nil, fmt.Errorf("error: pool size %d must be at least 1", poolSize)
+	}
+	opts = opts.withDefaults()
+	server := &TileServer{opts: opts, pool: make(chan Dataset, poolSize)}
+	for i := 0; i < poolSize; i++ {
+		src, err := Open(filename, ReadOnly)
+		if err != nil {
+			server.Close()
+			return nil, err
+		}
+		if i == 0 {
+			if src.Projection() == "" {
+				err = fmt.Errorf("error: dataset has no spatial reference")
+			} else {
+				server.opts.ranges, err = renderRanges(src, opts)
+			}
+			if err != nil {
+				src.Close()
+				return nil, err
+			}
+		}
+		server.pool <- src
+		server.size++
+	}
+	return server, nil
+}
+
+// ServeHTTP implements http.Handler. The last three path elements are used
+// as zoom, column and row, so the server can be mounted under any prefix.
+// Tiles without valid pixels are answered with 204 No Content.
+func (server *TileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	z, x, y, ok := parseTilePath(r.URL.Path, server.opts.Format)
+	if ok && z <= maxTileZoom {
+		width, height := server.opts.TileMatrixSet.MatrixSize(z)
+		ok = x < width && y < height
+	} else {
+		ok = false
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	var ds Dataset
+	select {
+	case ds = <-server.pool:
+	case <-r.Context().Done():
+		return
+	}
+	data, err := RenderTile(ds, z, x, y, server.opts)
+	server.pool <- ds
+
+	switch {
+	case errors.Is(err, ErrEmptyTile):
+		w.WriteHeader(http.StatusNoContent)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.Header().Set("Content-Type", server.opts.Format.MIMEType())
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Write(data)
+	}
+}
+
+// Close waits for pending requests and closes the pooled datasets.
+func (server *TileServer) Close() error {
+	for ; server.size > 0; server.size-- {
+		ds := <-server.pool
+		ds.Close()
+	}
+	return nil
+}
+
+// parseTilePath parses the zoom, column and row of a path ending with
+// /{z}/{x}/{y}.{ext}.
+func parseTilePath(path string, format TileFormat) (z, x, y int, ok bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 3 {
+		return 0, 0, 0, false
+	}
+	parts = parts[len(parts)-3:]
+	last := strings.TrimSuffix(parts[2], "."+format.Extension())
+	if last == parts[2] {
+		return 0, 0, 0, false
+	}
+
+	values := make([]int, 3)
+	for i, text := range []string{parts[0], parts[1], last} {
+		value, err := strconv.Atoi(text)
+		if err != nil || value < 0 {
+			return 0, 0, 0, false
+		}
+		values[i] = value
+	}
+	return values[0], values[1], values[2], true
+}
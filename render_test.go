@@ -0,0 +1,177 @@
+package gdal
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func decodeTile(t *testing.T, data []byte) image.Image {
+	t.Helper()
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png.Decode: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 256 || bounds.Dy() != 256 {
+		t.Fatalf("tile size = %v, want 256x256", bounds)
+	}
+	return img
+}
+
+func TestRenderTile(t *testing.T) {
+	src := createTileSource(t, "./tmp/render_source.tif")
+	defer src.Close()
+
+	full := decodeTile(t, mustRenderTile(t, src, 14, 8192, 8192, RenderOptions{}))
+	if _, _, _, a := full.At(128, 128).RGBA(); a == 0 {
+		t.Error("center of a covered tile is transparent")
+	}
+
+	partial := decodeTile(t, mustRenderTile(t, src, 13, 4096, 4096, RenderOptions{}))
+	if _, _, _, a := partial.At(0, 0).RGBA(); a == 0 {
+		t.Error("top-left pixel of tile 13/4096/4096 is transparent")
+	}
+	if _, _, _, a := partial.At(255, 255).RGBA(); a != 0 {
+		t.Error("bottom-right pixel of tile 13/4096/4096 is not transparent")
+	}
+
+	if _, err := RenderTile(src, 1, 0, 0, RenderOptions{}); !errors.Is(err, ErrEmptyTile) {
+		t.Errorf("RenderTile(1/0/0) error = %v, want ErrEmptyTile", err)
+	}
+	if _, err := RenderTile(src, 1, 2, 0, RenderOptions{}); err == nil {
+		t.Error("expected error for a tile outside of the matrix")
+	}
+
+	geographic, err := Warp("", nil, []Dataset{src}, []string{"-t_srs", "EPSG:4326"})
+	if err != nil {
+		t.Fatalf("Warp: %v", err)
+	}
+	defer geographic.Close()
+	decodeTile(t, mustRenderTile(t, geographic, 14, 8192, 8192, RenderOptions{}))
+}
+
+func TestRenderTileReprojectedFootprint(t *testing.T) {
+	filename := "./tmp/render_geographic.tif"
+	defer os.Remove(filename)
+
+	src := createGTiffRasterDataset(t, filename, 64, 64, 1)
+	defer src.Close()
+	sr := createSpatialReferenceFromEPSG(t, 4326)
+	defer sr.Destroy()
+	wkt, err := sr.ToWKT()
+	if err != nil {
+		t.Fatalf("ToWKT: %v", err)
+	}
+	if err := src.SetProjection(wkt); err != nil {
+		t.Fatalf("SetProjection: %v", err)
+	}
+	// Covers longitudes 0 to 0.64 and latitudes -0.64 to 0, the top-left
+	// part of tile 8/128/128.
+	if err := src.SetGeoTransform([6]float64{0, 0.01, 0, 0, 0, -0.01}); err != nil {
+		t.Fatalf("SetGeoTransform: %v", err)
+	}
+
+	img := decodeTile(t, mustRenderTile(t, src, 8, 128, 128, RenderOptions{Resampling: GRA_Bilinear}))
+	for _, point := range []image.Point{{10, 10}, {60, 60}} {
+		if _, _, _, a := img.At(point.X, point.Y).RGBA(); a == 0 {
+			t.Errorf("pixel %v inside the footprint is transparent", point)
+		}
+	}
+	for _, point := range []image.Point{{200, 200}, {60, 200}, {200, 60}} {
+		if _, _, _, a := img.At(point.X, point.Y).RGBA(); a != 0 {
+			t.Errorf("pixel %v outside the footprint is not transparent", point)
+		}
+	}
+}
+
+func TestRenderTileColorMap(t *testing.T) {
+	src := createMemoryRasterDataset(t, 256, 256, 1, Float32)
+	defer src.Close()
+	sr := createSpatialReferenceFromEPSG(t, 3857)
+	defer sr.Destroy()
+	wkt, _ := sr.ToWKT()
+	if err := src.SetProjection(wkt); err != nil {
+		t.Fatalf("SetProjection: %v", err)
+	}
+	if err := src.SetGeoTransform([6]float64{0, 10, 0, 0, 0, -10}); err != nil {
+		t.Fatalf("SetGeoTransform: %v", err)
+	}
+	if err := src.RasterBand(1).Fill(1, 0); err != nil {
+		t.Fatalf("Fill: %v", err)
+	}
+
+	colorMap := ColorMap{Stops: []ColorStop{
+		{Value: 0, Color: color.NRGBA{255, 0, 0, 255}},
+		{Value: 2, Color: color.NRGBA{0, 0, 255, 255}},
+	}}
+	img := decodeTile(t, mustRenderTile(t, src, 14, 8192, 8192, RenderOptions{ColorMap: &colorMap}))
+	if got := color.NRGBAModel.Convert(img.At(10, 10)); got != (color.NRGBA{128, 0, 128, 255}) {
+		t.Errorf("pixel = %v, want purple", got)
+	}
+
+	img = decodeTile(t, mustRenderTile(t, src, 14, 8192, 8192, RenderOptions{Scale: &RenderScale{Min: 0, Max: 4}}))
+	if got := color.NRGBAModel.Convert(img.At(10, 10)); got != (color.NRGBA{64, 64, 64, 255}) {
+		t.Errorf("pixel = %v, want gray 64", got)
+	}
+
+	if _, err := RenderTile(src, 14, 8192, 8192, RenderOptions{}); err == nil {
+		t.Error("expected error for a Float32 band without statistics or scale")
+	}
+	percent := ColorMap{Stops: []ColorStop{{Value: 0, Percent: true}, {Value: 100, Percent: true}}}
+	if _, err := RenderTile(src, 14, 8192, 8192, RenderOptions{ColorMap: &percent}); err == nil {
+		t.Error("expected error for percent stops without statistics or scale")
+	}
+	if _, err := src.RasterBand(1).GetStatistics(0, 0); err == nil {
+		t.Error("RenderTile computed statistics on the source dataset")
+	}
+}
+
+func mustRenderTile(t *testing.T, ds Dataset, z, x, y int, opts RenderOptions) []byte {
+	t.Helper()
+
+	data, err := RenderTile(ds, z, x, y, opts)
+	if err != nil {
+		t.Fatalf("RenderTile(%d/%d/%d): %v", z, x, y, err)
+	}
+	return data
+}
+
+func TestTileServer(t *testing.T) {
+	src := createTileSource(t, "./tmp/tile_server_source.tif")
+	src.Close()
+
+	server, err := NewTileServer("./tmp/tile_server_source.tif", 2, RenderOptions{})
+	if err != nil {
+		t.Fatalf("NewTileServer: %v", err)
+	}
+	defer server.Close()
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/13/4096/4096.png", http.StatusOK},
+		{"/tiles/14/8192/8192.png", http.StatusOK},
+		{"/1/0/0.png", http.StatusNoContent},
+		{"/1/1/1.jpg", http.StatusNotFound},
+		{"/1/2/0.png", http.StatusNotFound},
+		{"/a/b/c.png", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, test.path, nil))
+		if recorder.Code != test.status {
+			t.Errorf("GET %s = %d, want %d", test.path, recorder.Code, test.status)
+		}
+		if test.status == http.StatusOK && recorder.Header().Get("Content-Type") != "image/png" {
+			t.Errorf("GET %s Content-Type = %q", test.path, recorder.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package gdal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Statistics holds raster band statistics.
+type Statistics struct {
+	Min, Max, Mean, StdDev float64
+	// ValidPercent is the percentage of valid pixels, taken from the
+	// STATISTICS_VALID_PERCENT metadata item. It is only meaningful when
+	// HasValidPercent is set.
+	ValidPercent float64
+	// HasValidPercent reports whether GDAL reported ValidPercent, telling a
+	// band without valid pixels apart from a driver that does not report it.
+	HasValidPercent bool
+	// ValidCount is ValidPercent applied to the full band size. It is only
+	// an estimate of the number of valid pixels when Approximate is set,
+	// since ValidPercent then comes from an overview or a block subsample.
+	ValidCount int
+	// Approximate reports whether the statistics were computed from overviews
+	// or a subsample of blocks.
+	Approximate bool
+}
+
+func (rasterBand RasterBand) newStatistics(min, max, mean, stdDev float64) Statistics {
+	stats := Statistics{Min: min, Max: max, Mean: mean, StdDev: stdDev}
+
+	if value := rasterBand.MetadataItem("STATISTICS_VALID_PERCENT", ""); value != "" {
+		if percent, err := strconv.ParseFloat(value, 64); err == nil {
+			stats.ValidPercent = percent
+			stats.HasValidPercent = true
+			total := float64(rasterBand.XSize()) * float64(rasterBand.YSize())
+			stats.ValidCount = int(math.Round(total * percent / 100))
+		}
+	}
+	stats.Approximate = strings.EqualFold(rasterBand.MetadataItem("STATISTICS_APPROXIMATE", ""), "YES")
+
+	return stats
+}
+
+// contextProgress wraps progress so that it stops the operation once ctx is
+// done.
+func contextProgress(ctx context.Context, progress ProgressFunc) ProgressFunc {
+	return func(complete float64, message string, data interface{}) int {
+		if ctx.Err() != nil {
+			return 0
+		}
+		if progress == nil {
+			return 1
+		}
+		return progress(complete, message, data)
+	}
+}
+
+// Percentiles returns the band values at the given percentiles in the
+// 0..100 range, e.g. 2 and 98 for a display stretch. They are interpolated
+// from a histogram with the given number of buckets spanning the band
+// minimum and maximum.
+func (rasterBand RasterBand) Percentiles(
+	percentiles []float64,
+	buckets int,
+	approxOK int,
+	progress ProgressFunc,
+	data interface{},
+) ([]float64, error) {
+	for _, p := range percentiles {
+		if p < 0 || p > 100 || math.IsNaN(p) {
+			return nil, fmt.Errorf("percentile %v is outside of the 0..100 range", p)
+		}
+	}
+
+	stats, err := rasterBand.GetStatistics(approxOK, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]float64, len(percentiles))
+	if stats.Min == stats.Max {
+		for i := range result {
+			result[i] = stats.Min
+		}
+		return result, nil
+	}
+
+	histogram, err := rasterBand.Histogram(stats.Min, stats.Max, buckets, 1, approxOK, progress, data)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range percentiles {
+		result[i] = HistogramPercentile(histogram, stats.Min, stats.Max, p)
+	}
+	return result, nil
+}
+
+// HistogramPercentile returns the value at percentile p (0..100) of a
+// histogram whose buckets evenly span [min, max], interpolating linearly
+// within the bucket. NaN is returned for an empty histogram.
+func HistogramPercentile(histogram []int, min, max, p float64) float64 {
+	total := 0
+	for _, count := range histogram {
+		total += count
+	}
+	if total == 0 {
+		return math.NaN()
+	}
+
+	p = math.Max(0, math.Min(100, p))
+	target := p / 100 * float64(total)
+	width := (max - min) / float64(len(histogram))
+
+	cumulative := 0.0
+	for i, count := range histogram {
+		if count == 0 {
+			continue
+		}
+		next := cumulative + float64(count)
+		if next >= target {
+			fraction := (target - cumulative) / float64(count)
+			return min + width*(float64(i)+fraction)
+		}
+		cumulative = next
+	}
+	return max
+}
@@ -0,0 +1,100 @@
+package gdal
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestHistogramPercentile(t *testing.T) {
+	histogram := []int{0, 10, 10, 0}
+
+	tests := []struct {
+		p, want float64
+	}{
+		{0, 1},
+		{25, 1.5},
+		{50, 2},
+		{100, 3},
+	}
+	for _, tt := range tests {
+		if got := HistogramPercentile(histogram, 0, 4, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("HistogramPercentile(p=%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+
+	if got := HistogramPercentile([]int{0, 0}, 0, 1, 50); !math.IsNaN(got) {
+		t.Errorf("HistogramPercentile(empty) = %v, want NaN", got)
+	}
+}
+
+func TestComputeStatisticsReportsValidPercent(t *testing.T) {
+	ds := createMemoryRasterDataset(t, 4, 1, 1, Byte)
+	defer ds.Close()
+
+	band := ds.RasterBand(1)
+	if err := band.IO(Write, 0, 0, 4, 1, []uint8{0, 2, 4, 6}, 4, 1, 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Write): %v", err)
+	}
+	if err := band.SetNoDataValue(0); err != nil {
+		t.Fatalf("SetNoDataValue: %v", err)
+	}
+
+	stats, err := band.ComputeStatistics(context.Background(), 0, DummyProgress, nil)
+	if err != nil {
+		t.Fatalf("ComputeStatistics: %v", err)
+	}
+	if stats.Min != 2 || stats.Max != 6 || stats.Mean != 4 {
+		t.Errorf("ComputeStatistics() = %+v, want min 2 max 6 mean 4", stats)
+	}
+	if !stats.HasValidPercent || stats.ValidPercent != 75 || stats.ValidCount != 3 {
+		t.Errorf("valid = (%v%%, %d), want (75%%, 3)", stats.ValidPercent, stats.ValidCount)
+	}
+
+	cached, err := band.GetStatistics(0, 0)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if cached != stats {
+		t.Errorf("GetStatistics() = %+v, want %+v", cached, stats)
+	}
+}
+
+func TestComputeStatisticsHonoursContext(t *testing.T) {
+	ds := createFilledMemoryRasterDataset(t, 64, 64)
+	defer ds.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ds.RasterBand(1).ComputeStatistics(ctx, 0, nil, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("ComputeStatistics(cancelled) error = %v, want context.Canceled", err)
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	ds := createMemoryRasterDataset(t, 100, 1, 1, Float64)
+	defer ds.Close()
+
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	band := ds.RasterBand(1)
+	if err := band.IO(Write, 0, 0, 100, 1, values, 100, 1, 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Write): %v", err)
+	}
+
+	got, err := band.Percentiles([]float64{2, 98}, 1000, 0, nil, nil)
+	if err != nil {
+		t.Fatalf("Percentiles: %v", err)
+	}
+	if math.Abs(got[0]-2) > 1 || math.Abs(got[1]-98) > 1 {
+		t.Errorf("Percentiles(2, 98) = %v, want about [2 98]", got)
+	}
+
+	if _, err := band.Percentiles([]float64{101}, 10, 0, nil, nil); err == nil {
+		t.Error("expected error for percentile above 100")
+	}
+}
@@ -0,0 +1,144 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_gdal_subdataset.h"
+*/
+import "C"
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Subdataset describes an entry of the SUBDATASETS metadata domain.
+type Subdataset struct {
+	// Name is the connection string passed to Open.
+	Name string
+	// Description is the human readable description reported by the driver.
+	Description string
+}
+
+// Open opens the subdataset.
+func (subdataset Subdataset) Open(access Access) (Dataset, error) {
+	return Open(subdataset.Name, access)
+}
+
+// Subdatasets returns the subdatasets of a container dataset such as
+// NetCDF, HDF5 or a GeoPackage with several raster tables.
+func (dataset Dataset) Subdatasets() []Subdataset {
+	byIndex := map[int]*Subdataset{}
+	for _, item := range dataset.Metadata("SUBDATASETS") {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], "SUBDATASET_") {
+			continue
+		}
+		key := strings.TrimPrefix(parts[0], "SUBDATASET_")
+		separator := strings.Index(key, "_")
+		if separator < 0 {
+			continue
+		}
+		index, err := strconv.Atoi(key[:separator])
+		if err != nil {
+			continue
+		}
+		subdataset, ok := byIndex[index]
+		if !ok {
+			subdataset = &Subdataset{}
+			byIndex[index] = subdataset
+		}
+		switch key[separator+1:] {
+		case "NAME":
+			subdataset.Name = parts[1]
+		case "DESC":
+			subdataset.Description = parts[1]
+		}
+	}
+
+	indexes := make([]int, 0, len(byIndex))
+	for index := range byIndex {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	subdatasets := make([]Subdataset, 0, len(indexes))
+	for _, index := range indexes {
+		if byIndex[index].Name != "" {
+			subdatasets = append(subdatasets, *byIndex[index])
+		}
+	}
+	return subdatasets
+}
+
+// OpenSubdataset opens the subdataset at the 0-based index of Subdatasets.
+func (dataset Dataset) OpenSubdataset(index int, access Access) (Dataset, error) {
+	subdatasets := dataset.Subdatasets()
+	if index < 0 || index >= len(subdatasets) {
+		return Dataset{}, fmt.Errorf("subdataset index %d is out of range [0, %d)", index, len(subdatasets))
+	}
+	return subdatasets[index].Open(access)
+}
+
+// SubdatasetInfo holds the components of a subdataset name.
+type SubdatasetInfo struct {
+	// Path is the file name of the container.
+	Path string
+	// Component identifies the subdataset within the container, e.g. a
+	// NetCDF variable or a GeoPackage table.
+	Component string
+}
+
+// ParseSubdatasetName splits a subdataset name such as
+// NETCDF:"file.nc":variable into its path and component. GDAL 3.8 or newer
+// parses driver specific syntaxes, older versions fall back to the common
+// DRIVER:path:component form.
+func ParseSubdatasetName(name string) (SubdatasetInfo, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var cPath, cComponent *C.char
+	switch C.go_GetSubdatasetInfo(cName, &cPath, &cComponent) {
+	case 1:
+		return SubdatasetInfo{
+			Path:      goStringAndCPLFree(cPath),
+			Component: goStringAndCPLFree(cComponent),
+		}, nil
+	case 0:
+		return SubdatasetInfo{}, fmt.Errorf("subdataset name %q is not recognized", name)
+	}
+	return parseSubdatasetName(name)
+}
+
+// parseSubdatasetName parses DRIVER:path:component and
+// DRIVER:"path":component names.
+func parseSubdatasetName(name string) (SubdatasetInfo, error) {
+	separator := strings.Index(name, ":")
+	if separator <= 0 {
+		return SubdatasetInfo{}, fmt.Errorf("subdataset name %q is not recognized", name)
+	}
+	rest := name[separator+1:]
+
+	if strings.HasPrefix(rest, `"`) {
+		end := strings.Index(rest[1:], `"`)
+		if end < 0 {
+			return SubdatasetInfo{}, fmt.Errorf("subdataset name %q has an unterminated path", name)
+		}
+		path := rest[1 : end+1]
+		component := strings.TrimPrefix(rest[end+2:], ":")
+		return SubdatasetInfo{Path: path, Component: component}, nil
+	}
+
+	// Skip a Windows drive letter such as C:\ when looking for the separator.
+	start := 0
+	if len(rest) > 2 && rest[1] == ':' && (rest[2] == '\\' || rest[2] == '/') {
+		start = 2
+	}
+	separator = strings.LastIndex(rest[start:], ":")
+	if separator < 0 {
+		return SubdatasetInfo{}, fmt.Errorf("subdataset name %q has no component", name)
+	}
+	separator += start
+	return SubdatasetInfo{Path: rest[:separator], Component: rest[separator+1:]}, nil
+}
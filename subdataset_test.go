@@ -0,0 +1,106 @@
+package gdal
+
+import (
+	"fmt"
+	"testing"
+)
+
+func createGPKGRasterTable(t *testing.T, filename, table string, appendSubdataset bool) {
+	t.Helper()
+
+	driver, err := GetDriverByName(DriverNameGPKG)
+	if err != nil {
+		t.Fatalf("GetDriverByName(GPKG): %v", err)
+	}
+
+	options := []string{"RASTER_TABLE=" + table}
+	if appendSubdataset {
+		options = append(options, "APPEND_SUBDATASET=YES")
+	}
+	ds := driver.Create(filename, 4, 4, 1, Byte, options)
+	if ds.cval == nil {
+		t.Fatalf("GPKG Create(%s) returned nil dataset", table)
+	}
+	defer ds.Close()
+
+	if err := ds.SetGeoTransform([6]float64{0, 1, 0, 4, 0, -1}); err != nil {
+		t.Fatalf("SetGeoTransform: %v", err)
+	}
+	if err := ds.RasterBand(1).Fill(1, 0); err != nil {
+		t.Fatalf("Fill: %v", err)
+	}
+}
+
+func TestSubdatasets(t *testing.T) {
+	filename := "/vsimem/subdatasets.gpkg"
+	createGPKGRasterTable(t, filename, "first", false)
+	createGPKGRasterTable(t, filename, "second", true)
+
+	ds, err := Open(filename, ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer ds.Close()
+
+	subdatasets := ds.Subdatasets()
+	if len(subdatasets) != 2 {
+		t.Fatalf("Subdatasets() = %v, want 2 entries", subdatasets)
+	}
+	for i, table := range []string{"first", "second"} {
+		if want := fmt.Sprintf("GPKG:%s:%s", filename, table); subdatasets[i].Name != want {
+			t.Errorf("Subdatasets()[%d].Name = %q, want %q", i, subdatasets[i].Name, want)
+		}
+		if subdatasets[i].Description == "" {
+			t.Errorf("Subdatasets()[%d].Description is empty", i)
+		}
+	}
+
+	sub, err := ds.OpenSubdataset(1, ReadOnly)
+	if err != nil {
+		t.Fatalf("OpenSubdataset: %v", err)
+	}
+	defer sub.Close()
+	if sub.RasterXSize() != 4 {
+		t.Errorf("RasterXSize() = %d, want 4", sub.RasterXSize())
+	}
+
+	if _, err := ds.OpenSubdataset(2, ReadOnly); err == nil {
+		t.Error("expected error for out of range index")
+	}
+
+	info, err := ParseSubdatasetName(subdatasets[1].Name)
+	if err != nil {
+		t.Fatalf("ParseSubdatasetName: %v", err)
+	}
+	if info.Path != filename || info.Component != "second" {
+		t.Errorf("ParseSubdatasetName() = %+v", info)
+	}
+}
+
+func TestParseSubdatasetNameFallback(t *testing.T) {
+	tests := []struct {
+		name string
+		want SubdatasetInfo
+	}{
+		{`NETCDF:"/data/file.nc":temperature`, SubdatasetInfo{Path: "/data/file.nc", Component: "temperature"}},
+		{`HDF5:"C:\data\file.h5"://group/var`, SubdatasetInfo{Path: `C:\data\file.h5`, Component: "//group/var"}},
+		{`GPKG:/data/tiles.gpkg:layer`, SubdatasetInfo{Path: "/data/tiles.gpkg", Component: "layer"}},
+		{`GPKG:C:\data\tiles.gpkg:layer`, SubdatasetInfo{Path: `C:\data\tiles.gpkg`, Component: "layer"}},
+	}
+	for _, tt := range tests {
+		got, err := parseSubdatasetName(tt.name)
+		if err != nil {
+			t.Errorf("parseSubdatasetName(%q): %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSubdatasetName(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, name := range []string{"plain.tif", `NETCDF:"unterminated`, "GPKG:nocomponent"} {
+		if _, err := parseSubdatasetName(name); err == nil {
+			t.Errorf("parseSubdatasetName(%q): expected error", name)
+		}
+	}
+}
@@ -0,0 +1,475 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_gdal_tiles.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// TileMatrixSet describes a quad-tree tiling scheme as defined by the OGC
+// Two Dimensional Tile Matrix Set standard.
+type TileMatrixSet struct {
+	Identifier string
+	// SRS is the spatial reference of the tiles, e.g. "EPSG:3857".
+	SRS string
+	// MinX, MinY, MaxX and MaxY are the bounds of the tile matrix set.
+	MinX, MinY, MaxX, MaxY float64
+	// MatrixWidth and MatrixHeight are the number of tiles at zoom level 0.
+	MatrixWidth, MatrixHeight int
+	// TileSize is the width and height of a tile in pixels.
+	TileSize int
+}
+
+const webMercatorHalfWorld = 20037508.342789244
+
+var (
+	// WebMercatorQuad is the Google Maps compatible tiling scheme in EPSG:3857.
+	WebMercatorQuad = TileMatrixSet{
+		Identifier:   "WebMercatorQuad",
+		SRS:          "EPSG:3857",
+		MinX:         -webMercatorHalfWorld,
+		MinY:         -webMercatorHalfWorld,
+		MaxX:         webMercatorHalfWorld,
+		MaxY:         webMercatorHalfWorld,
+		MatrixWidth:  1,
+		MatrixHeight: 1,
+		TileSize:     256,
+	}
+	// WorldCRS84Quad is the geographic tiling scheme with two tiles at zoom 0.
+	WorldCRS84Quad = TileMatrixSet{
+		Identifier:   "WorldCRS84Quad",
+		SRS:          "EPSG:4326",
+		MinX:         -180,
+		MinY:         -90,
+		MaxX:         180,
+		MaxY:         90,
+		MatrixWidth:  2,
+		MatrixHeight: 1,
+		TileSize:     256,
+	}
+)
+
+// maxTileZoom is the highest zoom level supported by the tile generator.
+const maxTileZoom = 30
+
+// MatrixSize returns the number of tile columns and rows at zoom.
+func (tms TileMatrixSet) MatrixSize(zoom int) (width, height int) {
+	return tms.MatrixWidth << uint(zoom), tms.MatrixHeight << uint(zoom)
+}
+
+// Resolution returns the size of a pixel at zoom, in SRS units.
+func (tms TileMatrixSet) Resolution(zoom int) float64 {
+	width, _ := tms.MatrixSize(zoom)
+	return (tms.MaxX - tms.MinX) / float64(width*tms.TileSize)
+}
+
+// ZoomForResolution returns the lowest zoom level whose resolution is at
+// least as fine as resolution.
+func (tms TileMatrixSet) ZoomForResolution(resolution float64) int {
+	for zoom := 0; zoom < maxTileZoom; zoom++ {
+		if tms.Resolution(zoom) <= resolution*(1+1e-9) {
+			return zoom
+		}
+	}
+	return maxTileZoom
+}
+
+// TileBounds returns the bounds of the tile at column col and row row of
+// zoom. Rows are counted from the top of the matrix.
+func (tms TileMatrixSet) TileBounds(zoom, col, row int) (minX, minY, maxX, maxY float64) {
+	span := tms.Resolution(zoom) * float64(tms.TileSize)
+	minX = tms.MinX + float64(col)*span
+	maxY = tms.MaxY - float64(row)*span
+	return minX, maxY - span, minX + span, maxY
+}
+
+// TileRange returns the range of tiles of zoom intersecting the given bounds,
+// clamped to the matrix.
+func (tms TileMatrixSet) TileRange(zoom int, minX, minY, maxX, maxY float64) (minCol, minRow, maxCol, maxRow int) {
+	span := tms.Resolution(zoom) * float64(tms.TileSize)
+	width, height := tms.MatrixSize(zoom)
+	clamp := func(value, max int) int {
+		if value < 0 {
+			return 0
+		}
+		if value > max {
+			return max
+		}
+		return value
+	}
+	const epsilon = 1e-9
+	minCol = clamp(int(math.Floor((minX-tms.MinX)/span+epsilon)), width-1)
+	maxCol = clamp(int(math.Ceil((maxX-tms.MinX)/span-epsilon))-1, width-1)
+	minRow = clamp(int(math.Floor((tms.MaxY-maxY)/span+epsilon)), height-1)
+	maxRow = clamp(int(math.Ceil((tms.MaxY-minY)/span-epsilon))-1, height-1)
+	return minCol, minRow, maxCol, maxRow
+}
+
+// Tile identifies a tile of a tile matrix set. Rows are counted from the
+// top of the matrix, as in XYZ tile URLs.
+type Tile struct {
+	Zoom, Col, Row int
+}
+
+// TileFormat is the image format of generated tiles.
+type TileFormat string
+
+const (
+	TileFormatPNG  = TileFormat("PNG")
+	TileFormatJPEG = TileFormat("JPEG")
+	TileFormatWEBP = TileFormat("WEBP")
+)
+
+// Extension returns the file extension of the format.
+func (format TileFormat) Extension() string {
+	switch format {
+	case TileFormatJPEG:
+		return "jpg"
+	case TileFormatWEBP:
+		return "webp"
+	default:
+		return "png"
+	}
+}
+
+// MIMEType returns the media type of the format.
+func (format TileFormat) MIMEType() string {
+	switch format {
+	case TileFormatJPEG:
+		return "image/jpeg"
+	case TileFormatWEBP:
+		return "image/webp"
+	default:
+		return "image/png"
+	}
+}
+
+// TileOptions describes a tile pyramid generation.
+type TileOptions struct {
+	// TileMatrixSet defaults to WebMercatorQuad.
+	TileMatrixSet TileMatrixSet
+	// MinZoom and MaxZoom are the zoom levels to generate. A negative
+	// MaxZoom selects the zoom level matching the source resolution.
+	MinZoom, MaxZoom int
+	// Resampling is the gdalwarp resampling method, "average" by default.
+	Resampling string
+	// Format defaults to TileFormatPNG.
+	Format TileFormat
+	// Quality is the JPEG or WEBP quality, between 1 and 100.
+	Quality int
+	// Workers is the number of tiles rendered concurrently, the number of
+	// CPUs by default.
+	Workers int
+	// Resume skips the tiles already present in the sink.
+	Resume bool
+}
+
+func (opts TileOptions) withDefaults() TileOptions {
+	if opts.TileMatrixSet.Identifier == "" {
+		opts.TileMatrixSet = WebMercatorQuad
+	}
+	if opts.Resampling == "" {
+		opts.Resampling = "average"
+	}
+	if opts.Format == "" {
+		opts.Format = TileFormatPNG
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	return opts
+}
+
+// TileSink stores generated tiles. GenerateTiles calls its methods from one
+// goroutine at a time.
+type TileSink interface {
+	// HasTile reports whether tile is already stored.
+	HasTile(tile Tile) (bool, error)
+	// WriteTile stores the encoded image of tile.
+	WriteTile(tile Tile, data []byte) error
+	// Close flushes and releases the sink.
+	Close() error
+}
+
+// tileSinkStarter is implemented by sinks that record the pyramid layout
+// before the first tile is written.
+type tileSinkStarter interface {
+	start(opts TileOptions, minX, minY, maxX, maxY float64) error
+}
+
+// tileResult is a rendered, skipped or failed tile.
+type tileResult struct {
+	tile Tile
+	data []byte
+	err  error
+}
+
+// GenerateTiles renders the tiles of src between opts.MinZoom and
+// opts.MaxZoom and stores them in sink. Tiles are warped to the tile matrix
+// set with Warp and the MEM driver, then encoded with Translate, in parallel.
+// Fully transparent tiles are skipped. src must have Byte bands, optionally
+// with an alpha band; palette bands must be expanded first.
+func GenerateTiles(src Dataset, sink TileSink, opts TileOptions, progress ProgressFunc, data interface{}) error {
+	opts = opts.withDefaults()
+	tms := opts.TileMatrixSet
+
+	if err := checkTileSource(src, opts.Format); err != nil {
+		return err
+	}
+
+	cSRS := C.CString(tms.SRS)
+	defer C.free(unsafe.Pointer(cSRS))
+	extent := make([]C.double, 4)
+	var resolution C.double
+	if C.go_TransformedExtent(src.cval, cSRS, &extent[0], &resolution) == 0 {
+		return fmt.Errorf("error: cannot compute the extent of the dataset in %s", tms.SRS)
+	}
+	minX, minY, maxX, maxY := float64(extent[0]), float64(extent[1]), float64(extent[2]), float64(extent[3])
+
+	if opts.MaxZoom < 0 {
+		opts.MaxZoom = tms.ZoomForResolution(float64(resolution))
+	}
+	if opts.MinZoom < 0 || opts.MinZoom > opts.MaxZoom || opts.MaxZoom > maxTileZoom {
+		return fmt.Errorf("error: invalid zoom range %d-%d", opts.MinZoom, opts.MaxZoom)
+	}
+
+	if starter, ok := sink.(tileSinkStarter); ok {
+		if err := starter.start(opts, minX, minY, maxX, maxY); err != nil {
+			return err
+		}
+	}
+
+	total := 0
+	for zoom := opts.MinZoom; zoom <= opts.MaxZoom; zoom++ {
+		minCol, minRow, maxCol, maxRow := tms.TileRange(zoom, minX, minY, maxX, maxY)
+		total += (maxCol - minCol + 1) * (maxRow - minRow + 1)
+	}
+
+	// Dataset handles cannot be shared between threads: workers open their
+	// own handle when src is backed by files, and take turns otherwise.
+	shared := len(src.FileList()) == 0
+	var sharedMutex, sinkMutex sync.Mutex
+
+	done := make(chan struct{})
+	jobs := make(chan Tile, opts.Workers)
+	results := make(chan tileResult, opts.Workers)
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for zoom := opts.MinZoom; zoom <= opts.MaxZoom; zoom++ {
+			minCol, minRow, maxCol, maxRow := tms.TileRange(zoom, minX, minY, maxX, maxY)
+			for row := minRow; row <= maxRow; row++ {
+				for col := minCol; col <= maxCol; col++ {
+					tile := Tile{Zoom: zoom, Col: col, Row: row}
+					if opts.Resume {
+						sinkMutex.Lock()
+						exists, err := sink.HasTile(tile)
+						sinkMutex.Unlock()
+						if err != nil || exists {
+							select {
+							case results <- tileResult{tile: tile, err: err}:
+								continue
+							case <-done:
+								return
+							}
+						}
+					}
+					select {
+					case jobs <- tile:
+					case <-done:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ds := src
+			if !shared {
+				opened, err := Open(src.Description(), ReadOnly)
+				if err != nil {
+					select {
+					case results <- tileResult{err: err}:
+					case <-done:
+					}
+					return
+				}
+				defer opened.Close()
+				ds = opened
+			}
+			for tile := range jobs {
+				select {
+				case <-done:
+					continue
+				default:
+				}
+				if shared {
+					sharedMutex.Lock()
+				}
+				encoded, err := renderTile(ds, tile, opts)
+				if shared {
+					sharedMutex.Unlock()
+				}
+				select {
+				case results <- tileResult{tile: tile, data: encoded, err: err}:
+				case <-done:
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var err error
+	completed := 0
+	for result := range results {
+		if err != nil {
+			continue
+		}
+		if result.err != nil {
+			err = result.err
+		} else if result.data != nil {
+			sinkMutex.Lock()
+			err = sink.WriteTile(result.tile, result.data)
+			sinkMutex.Unlock()
+		}
+		completed++
+		if err == nil && progress != nil && progress(float64(completed)/float64(total), "", data) == 0 {
+			err = fmt.Errorf("error: tile generation canceled")
+		}
+		if err != nil {
+			close(done)
+		}
+	}
+	return err
+}
+
+// checkTileSource checks that src can be encoded to format.
+func checkTileSource(src Dataset, format TileFormat) error {
+	count := src.RasterCount()
+	if count == 0 {
+		return fmt.Errorf("error: dataset has no raster band")
+	}
+	for i := 1; i <= count; i++ {
+		band := src.RasterBand(i)
+		if band.RasterDataType() != Byte {
+			return fmt.Errorf("error: band %d must be of type Byte", i)
+		}
+		if band.ColorInterp() == CI_PaletteIndex {
+			return fmt.Errorf("error: band %d is a palette band, expand it to RGB first", i)
+		}
+	}
+	colors := count
+	if src.RasterBand(count).ColorInterp() == CI_AlphaBand {
+		colors--
+	}
+	switch {
+	case colors != 1 && colors != 3:
+		return fmt.Errorf("error: tiles need 1 or 3 color bands, got %d", colors)
+	case format != TileFormatPNG && format != TileFormatJPEG && format != TileFormatWEBP:
+		return fmt.Errorf("error: unsupported tile format %q", format)
+	}
+	return nil
+}
+
+var tileFileCounter int64
+
+// renderTile warps ds to tile and returns the encoded image, or nil when the
+// tile is fully transparent.
+func renderTile(ds Dataset, tile Tile, opts TileOptions) ([]byte, error) {
+	tms := opts.TileMatrixSet
+	warped, err := warpTile(ds, tms, tile.Zoom, tile.Col, tile.Row, opts.Resampling)
+	if err != nil {
+		return nil, err
+	}
+	defer warped.Close()
+
+	count := warped.RasterCount()
+	alpha := make([]uint8, tms.TileSize*tms.TileSize)
+	if err := warped.RasterBand(count).IO(Read, 0, 0, tms.TileSize, tms.TileSize, alpha, tms.TileSize, tms.TileSize, 0, 0); err != nil {
+		return nil, err
+	}
+	empty := true
+	for _, value := range alpha {
+		if value != 0 {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return nil, nil
+	}
+
+	return encodeTile(warped, opts)
+}
+
+// warpTile warps ds to the tile at column col and row row of zoom in a MEM
+// dataset whose last band is alpha, transparent outside of ds and on nodata.
+func warpTile(ds Dataset, tms TileMatrixSet, zoom, col, row int, resampling string) (Dataset, error) {
+	minX, minY, maxX, maxY := tms.TileBounds(zoom, col, row)
+	size := strconv.Itoa(tms.TileSize)
+
+	options := []string{
+		"-of", "MEM",
+		"-t_srs", tms.SRS,
+		"-te", formatFloat(minX), formatFloat(minY), formatFloat(maxX), formatFloat(maxY),
+		"-ts", size, size,
+		"-r", resampling,
+		"-dstalpha",
+	}
+	return Warp("", nil, []Dataset{ds}, options)
+}
+
+// encodeTile encodes the warped tile ds, whose last band is alpha, to the
+// tile format of opts.
+func encodeTile(ds Dataset, opts TileOptions) ([]byte, error) {
+	count := ds.RasterCount()
+	var bands []int
+	switch opts.Format {
+	case TileFormatJPEG:
+		for i := 1; i < count; i++ {
+			bands = append(bands, i)
+		}
+	case TileFormatWEBP:
+		if count == 2 {
+			bands = []int{1, 1, 1, 2}
+		}
+	}
+
+	options := []string{"-of", string(opts.Format)}
+	for _, band := range bands {
+		options = append(options, "-b", strconv.Itoa(band))
+	}
+	if opts.Quality > 0 && opts.Format != TileFormatPNG {
+		options = append(options, "-co", "QUALITY="+strconv.Itoa(opts.Quality))
+	}
+
+	filename := fmt.Sprintf("/vsimem/tile_%d.%s", atomic.AddInt64(&tileFileCounter, 1), opts.Format.Extension())
+	encoded, err := Translate(filename, ds, options)
+	if err != nil {
+		return nil, err
+	}
+	encoded.Close()
+	defer VSIUnlink(filename + ".aux.xml")
+
+	return VSIGetMemFileBuffer(filename, true)
+}
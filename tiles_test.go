@@ -0,0 +1,136 @@
+package gdal
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createTileSource creates a 256x256 single band GeoTIFF in EPSG:3857 whose
+// top-left corner is at the origin, with 10 meter pixels.
+func createTileSource(t *testing.T, filename string) Dataset {
+	t.Helper()
+
+	ds := createGTiffRasterDataset(t, filename, 256, 256, 1)
+	sr := createSpatialReferenceFromEPSG(t, 3857)
+	defer sr.Destroy()
+	wkt, err := sr.ToWKT()
+	if err != nil {
+		ds.Close()
+		t.Fatalf("ToWKT: %v", err)
+	}
+	if err := ds.SetProjection(wkt); err != nil {
+		ds.Close()
+		t.Fatalf("SetProjection: %v", err)
+	}
+	ds.FlushCache()
+	return ds
+}
+
+// countingTileSink counts the tiles written to a DirectoryTileSink.
+type countingTileSink struct {
+	*DirectoryTileSink
+	written int
+}
+
+func (sink *countingTileSink) WriteTile(tile Tile, data []byte) error {
+	sink.written++
+	return sink.DirectoryTileSink.WriteTile(tile, data)
+}
+
+func TestTileMatrixSet(t *testing.T) {
+	if got := WebMercatorQuad.Resolution(0); math.Abs(got-156543.03392804097) > 1e-6 {
+		t.Errorf("WebMercatorQuad.Resolution(0) = %v", got)
+	}
+	if width, height := WorldCRS84Quad.MatrixSize(2); width != 8 || height != 4 {
+		t.Errorf("WorldCRS84Quad.MatrixSize(2) = %dx%d, want 8x4", width, height)
+	}
+
+	minX, minY, maxX, maxY := WorldCRS84Quad.TileBounds(0, 1, 0)
+	if minX != 0 || minY != -90 || maxX != 180 || maxY != 90 {
+		t.Errorf("TileBounds(0, 1, 0) = %v %v %v %v", minX, minY, maxX, maxY)
+	}
+
+	minCol, minRow, maxCol, maxRow := WebMercatorQuad.TileRange(1, 0, -1000, 1000, 0)
+	if minCol != 1 || minRow != 1 || maxCol != 1 || maxRow != 1 {
+		t.Errorf("TileRange(1) = %d %d %d %d, want 1 1 1 1", minCol, minRow, maxCol, maxRow)
+	}
+	minCol, minRow, maxCol, maxRow = WebMercatorQuad.TileRange(2, -1e9, -1e9, 1e9, 1e9)
+	if minCol != 0 || minRow != 0 || maxCol != 3 || maxRow != 3 {
+		t.Errorf("TileRange(2, world) = %d %d %d %d, want 0 0 3 3", minCol, minRow, maxCol, maxRow)
+	}
+
+	if got := WebMercatorQuad.ZoomForResolution(10); got != 14 {
+		t.Errorf("ZoomForResolution(10) = %d, want 14", got)
+	}
+}
+
+func TestGenerateTilesDirectory(t *testing.T) {
+	src := createTileSource(t, "./tmp/tiles_source.tif")
+	defer src.Close()
+
+	root := "./tmp/tiles_tms"
+	os.RemoveAll(root)
+	sink := &countingTileSink{DirectoryTileSink: NewDirectoryTileSink(root, TileLayoutTMS, TileFormatPNG)}
+	opts := TileOptions{MinZoom: 0, MaxZoom: 2, Workers: 2}
+
+	var calls int
+	progress := func(complete float64, message string, data interface{}) int {
+		calls++
+		return 1
+	}
+	if err := GenerateTiles(src, sink, opts, progress, nil); err != nil {
+		t.Fatalf("GenerateTiles: %v", err)
+	}
+	if sink.written != 3 || calls != 3 {
+		t.Errorf("wrote %d tiles with %d progress calls, want 3 and 3", sink.written, calls)
+	}
+	for _, path := range []string{"0/0/0.png", "1/1/0.png", "2/2/1.png"} {
+		if _, err := os.Stat(filepath.Join(root, path)); err != nil {
+			t.Errorf("missing tile %s: %v", path, err)
+		}
+	}
+
+	os.Remove(filepath.Join(root, "2/2/1.png"))
+	sink.written = 0
+	opts.Resume = true
+	if err := GenerateTiles(src, sink, opts, nil, nil); err != nil {
+		t.Fatalf("GenerateTiles(resume): %v", err)
+	}
+	if sink.written != 1 {
+		t.Errorf("resume wrote %d tiles, want 1", sink.written)
+	}
+
+	cancel := func(complete float64, message string, data interface{}) int { return 0 }
+	if err := GenerateTiles(src, NewDirectoryTileSink(root, TileLayoutXYZ, TileFormatPNG), opts, cancel, nil); err == nil {
+		t.Error("expected error when progress cancels")
+	}
+}
+
+func TestGenerateTilesMemorySource(t *testing.T) {
+	file := createTileSource(t, "./tmp/tiles_memory_source.tif")
+	defer file.Close()
+	src, err := Translate("", file, nil)
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	defer src.Close()
+
+	root := "./tmp/tiles_xyz_webp"
+	os.RemoveAll(root)
+	sink := NewDirectoryTileSink(root, TileLayoutXYZ, TileFormatWEBP)
+	opts := TileOptions{MinZoom: 1, MaxZoom: 1, Format: TileFormatWEBP, Quality: 80, Workers: 4}
+	if err := GenerateTiles(src, sink, opts, nil, nil); err != nil {
+		t.Fatalf("GenerateTiles: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "1/1/1.webp")); err != nil {
+		t.Errorf("missing tile: %v", err)
+	}
+
+	float := createMemoryRasterDataset(t, 4, 4, 1, Float32)
+	defer float.Close()
+	if err := GenerateTiles(float, sink, opts, nil, nil); err == nil {
+		t.Error("expected error for Float32 source")
+	}
+}
@@ -0,0 +1,475 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "go_gdal_sqlite.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// TileLayout is the directory layout of a DirectoryTileSink.
+type TileLayout int
+
+const (
+	// TileLayoutXYZ stores tiles as {z}/{x}/{y} with rows counted from the top.
+	TileLayoutXYZ TileLayout = iota
+	// TileLayoutTMS stores tiles as {z}/{x}/{y} with rows counted from the bottom.
+	TileLayoutTMS
+	// TileLayoutWMTS stores tiles as {TileMatrix}/{TileRow}/{TileCol}.
+	TileLayoutWMTS
+)
+
+// DirectoryTileSink writes tiles as files below a root directory.
+type DirectoryTileSink struct {
+	Root   string
+	Layout TileLayout
+	Format TileFormat
+	tms    TileMatrixSet
+}
+
+// NewDirectoryTileSink returns a sink writing tiles of format below root.
+func NewDirectoryTileSink(root string, layout TileLayout, format TileFormat) *DirectoryTileSink {
+	return &DirectoryTileSink{Root: root, Layout: layout, Format: format, tms: WebMercatorQuad}
+}
+
+func (sink *DirectoryTileSink) start(opts TileOptions, minX, minY, maxX, maxY float64) error {
+	if sink.Format != opts.Format {
+		return fmt.Errorf("error: sink format %s does not match tile format %s", sink.Format, opts.Format)
+	}
+	sink.tms = opts.TileMatrixSet
+	return os.MkdirAll(sink.Root, 0755)
+}
+
+// Path returns the file path of tile.
+func (sink *DirectoryTileSink) Path(tile Tile) string {
+	first, second := tile.Col, tile.Row
+	switch sink.Layout {
+	case TileLayoutTMS:
+		_, height := sink.tms.MatrixSize(tile.Zoom)
+		second = height - 1 - tile.Row
+	case TileLayoutWMTS:
+		first, second = tile.Row, tile.Col
+	}
+	return filepath.Join(sink.Root, strconv.Itoa(tile.Zoom), strconv.Itoa(first),
+		strconv.Itoa(second)+"."+sink.Format.Extension())
+}
+
+// HasTile implements TileSink.
+func (sink *DirectoryTileSink) HasTile(tile Tile) (bool, error) {
+	_, err := os.Stat(sink.Path(tile))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// WriteTile implements TileSink.
+func (sink *DirectoryTileSink) WriteTile(tile Tile, data []byte) error {
+	path := sink.Path(tile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Close implements TileSink.
+func (sink *DirectoryTileSink) Close() error {
+	return nil
+}
+
+// execSQL runs statement on ds and returns the error GDAL reports, if any.
+func execSQL(ds DataSource, statement string) error {
+	// CPL errors are thread local.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	C.CPLErrorReset()
+	layer := ds.ExecuteSQL(statement, Geometry{}, "")
+	if layer.cval != nil {
+		ds.ReleaseResultSet(layer)
+	}
+	if C.CPLGetLastErrorType() >= C.CE_Failure {
+		return fmt.Errorf("sql error: %s", C.GoString(C.CPLGetLastErrorMsg()))
+	}
+	return nil
+}
+
+// queryEach runs query on ds and calls fn for each returned row.
+func queryEach(ds DataSource, query string, fn func(feature Feature) error) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	C.CPLErrorReset()
+	layer := ds.ExecuteSQL(query, Geometry{}, "")
+	if layer.cval == nil {
+		return fmt.Errorf("sql error: %s", C.GoString(C.CPLGetLastErrorMsg()))
+	}
+	defer ds.ReleaseResultSet(layer)
+
+	for feature := layer.NextFeature(); feature != nil; feature = layer.NextFeature() {
+		err := fn(*feature)
+		feature.Destroy()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryInt runs a query returning a single integer on ds.
+func queryInt(ds DataSource, query string) (int, error) {
+	value := 0
+	err := queryEach(ds, query, func(feature Feature) error {
+		value = feature.FieldAsInteger(0)
+		return nil
+	})
+	return value, err
+}
+
+// sqlQuote quotes value as an SQL string literal.
+func sqlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// sqlIdentifier quotes name as an SQL identifier.
+func sqlIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// openOrCreateSQLite opens filename for update with driverName, creating it
+// when it does not exist.
+func openOrCreateSQLite(driverName, filename string, options []string) (DataSource, error) {
+	driver := OGRDriverByName(driverName)
+	if driver.cval == nil {
+		return DataSource{}, fmt.Errorf("error: driver %s is not available", driverName)
+	}
+	if ds, ok := driver.Open(filename, 1); ok {
+		return ds, nil
+	}
+	ds, ok := driver.Create(filename, options)
+	if !ok {
+		return DataSource{}, fmt.Errorf("error: failed to create %q", filename)
+	}
+	return ds, nil
+}
+
+// tileBatchSize is the number of tiles a tileBlobWriter writes per
+// transaction.
+const tileBatchSize = 256
+
+// tileBlobWriter writes tiles to the tile table of an SQLite based data
+// source in batched transactions. OGR SQL cannot bind parameters, so tiles
+// are inserted with a statement prepared on the SQLite connection of the
+// data source, binding the blob instead of inlining it as a hex literal.
+type tileBlobWriter struct {
+	ds        DataSource
+	table     string
+	statement unsafe.Pointer
+	pending   int
+}
+
+func newTileBlobWriter(ds DataSource, table string) *tileBlobWriter {
+	return &tileBlobWriter{ds: ds, table: table}
+}
+
+// dataset returns the data source as a dataset, whose transactions drive
+// the SQLite connection the statement runs on.
+func (writer *tileBlobWriter) dataset() Dataset {
+	return Dataset{C.GDALDatasetH(unsafe.Pointer(writer.ds.cval))}
+}
+
+// open prepares the insert statement.
+func (writer *tileBlobWriter) open() error {
+	if writer.statement != nil {
+		return nil
+	}
+	// CPL errors are thread local.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	cSQL := C.CString(fmt.Sprintf(
+		"INSERT OR REPLACE INTO %s (zoom_level, tile_column, tile_row, tile_data) VALUES (?, ?, ?, ?)",
+		sqlIdentifier(writer.table)))
+	defer C.free(unsafe.Pointer(cSQL))
+	C.CPLErrorReset()
+	writer.statement = C.go_SQLitePrepare(writer.dataset().cval, cSQL)
+	if writer.statement == nil {
+		return fmt.Errorf("error: failed to prepare tile insertion into %q: %s",
+			writer.table, C.GoString(C.CPLGetLastErrorMsg()))
+	}
+	return nil
+}
+
+// insert runs the insert statement for a tile.
+func (writer *tileBlobWriter) insert(zoom, col, row int, data []byte) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	var cData unsafe.Pointer
+	if len(data) > 0 {
+		cData = unsafe.Pointer(&data[0])
+	}
+	C.CPLErrorReset()
+	ok := C.go_SQLiteInsertTile(writer.statement, C.int(zoom), C.int(col), C.int(row), cData, C.int(len(data)))
+	if ok == 0 {
+		return fmt.Errorf("sql error: %s", C.GoString(C.CPLGetLastErrorMsg()))
+	}
+	return nil
+}
+
+// write writes a tile, replacing any existing one, in the current batch.
+func (writer *tileBlobWriter) write(zoom, col, row int, data []byte) error {
+	if err := writer.open(); err != nil {
+		return err
+	}
+	if writer.pending == 0 {
+		if err := writer.dataset().StartTransaction(false); err != nil {
+			return err
+		}
+	}
+	if err := writer.insert(zoom, col, row, data); err != nil {
+		if writer.pending == 0 {
+			writer.dataset().RollbackTransaction()
+		}
+		return err
+	}
+	writer.pending++
+	if writer.pending >= tileBatchSize {
+		return writer.flush()
+	}
+	return nil
+}
+
+// has reports whether a tile is stored, including tiles of the current
+// batch.
+func (writer *tileBlobWriter) has(zoom, col, row int) (bool, error) {
+	count, err := queryInt(writer.ds, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s",
+		sqlIdentifier(writer.table), tileWhere(zoom, col, row)))
+	return count > 0, err
+}
+
+// flush commits the current batch.
+func (writer *tileBlobWriter) flush() error {
+	if writer.pending == 0 {
+		return nil
+	}
+	writer.pending = 0
+	if err := writer.dataset().CommitTransaction(); err != nil {
+		writer.dataset().RollbackTransaction()
+		return err
+	}
+	return nil
+}
+
+// close commits the current batch and releases the insert statement.
+func (writer *tileBlobWriter) close() error {
+	err := writer.flush()
+	if writer.statement != nil {
+		C.go_SQLiteFinalize(writer.statement)
+		writer.statement = nil
+	}
+	return err
+}
+
+// MBTilesSink writes tiles to an MBTiles file, in batched transactions.
+type MBTilesSink struct {
+	Name   string
+	ds     DataSource
+	writer *tileBlobWriter
+}
+
+// NewMBTilesSink opens or creates the MBTiles file filename. name is stored
+// in the metadata table.
+func NewMBTilesSink(filename, name string) (*MBTilesSink, error) {
+	ds, err := openOrCreateSQLite(OGRDriverNameSQLite, filename, []string{"METADATA=NO"})
+	if err != nil {
+		return nil, err
+	}
+	sink := &MBTilesSink{Name: name, ds: ds, writer: newTileBlobWriter(ds, "tiles")}
+	for _, statement := range []string{
+		"CREATE TABLE IF NOT EXISTS metadata (name TEXT, value TEXT)",
+		"CREATE UNIQUE INDEX IF NOT EXISTS metadata_name ON metadata (name)",
+		"CREATE TABLE IF NOT EXISTS tiles (zoom_level INTEGER, tile_column INTEGER, tile_row INTEGER, tile_data BLOB)",
+		"CREATE UNIQUE INDEX IF NOT EXISTS tile_index ON tiles (zoom_level, tile_column, tile_row)",
+	} {
+		if err := execSQL(ds, statement); err != nil {
+			ds.Destroy()
+			return nil, err
+		}
+	}
+	return sink, nil
+}
+
+func (sink *MBTilesSink) start(opts TileOptions, minX, minY, maxX, maxY float64) error {
+	if opts.TileMatrixSet.Identifier != WebMercatorQuad.Identifier {
+		return fmt.Errorf("error: MBTiles requires the %s tile matrix set", WebMercatorQuad.Identifier)
+	}
+	west, south := webMercatorToLonLat(minX, minY)
+	east, north := webMercatorToLonLat(maxX, maxY)
+	metadata := [][2]string{
+		{"name", sink.Name},
+		{"type", "overlay"},
+		{"version", "1.1"},
+		{"format", opts.Format.Extension()},
+		{"minzoom", strconv.Itoa(opts.MinZoom)},
+		{"maxzoom", strconv.Itoa(opts.MaxZoom)},
+		{"bounds", strings.Join([]string{formatFloat(west), formatFloat(south), formatFloat(east), formatFloat(north)}, ",")},
+	}
+	for _, item := range metadata {
+		statement := fmt.Sprintf("INSERT OR REPLACE INTO metadata (name, value) VALUES (%s, %s)",
+			sqlQuote(item[0]), sqlQuote(item[1]))
+		if err := execSQL(sink.ds, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mbtilesRow returns the bottom-origin MBTiles row of tile.
+func mbtilesRow(tile Tile) int {
+	return (1 << uint(tile.Zoom)) - 1 - tile.Row
+}
+
+// HasTile implements TileSink.
+func (sink *MBTilesSink) HasTile(tile Tile) (bool, error) {
+	return sink.writer.has(tile.Zoom, tile.Col, mbtilesRow(tile))
+}
+
+// WriteTile implements TileSink. Tiles are committed in batches; Close
+// commits the last one.
+func (sink *MBTilesSink) WriteTile(tile Tile, data []byte) error {
+	return sink.writer.write(tile.Zoom, tile.Col, mbtilesRow(tile), data)
+}
+
+// Close implements TileSink.
+func (sink *MBTilesSink) Close() error {
+	err := sink.writer.close()
+	sink.ds.Destroy()
+	return err
+}
+
+// webMercatorToLonLat converts EPSG:3857 coordinates to longitude and latitude.
+func webMercatorToLonLat(x, y float64) (lon, lat float64) {
+	const radius = 6378137.0
+	lon = x / radius * 180 / math.Pi
+	lat = (2*math.Atan(math.Exp(y/radius)) - math.Pi/2) * 180 / math.Pi
+	return lon, lat
+}
+
+// GPKGTileSink writes tiles to a tile pyramid table of a GeoPackage, in
+// batched transactions.
+type GPKGTileSink struct {
+	Table string
+	ds    DataSource
+	tiles *GPKGTiles
+}
+
+// NewGPKGTileSink opens or creates the GeoPackage filename and writes tiles
+// to table.
+func NewGPKGTileSink(filename, table string) (*GPKGTileSink, error) {
+	ds, err := openOrCreateSQLite(OGRDriverNameGPKG, filename, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GPKGTileSink{Table: table, ds: ds, tiles: newGPKGTiles(ds, table)}, nil
+}
+
+func (sink *GPKGTileSink) start(opts TileOptions, minX, minY, maxX, maxY float64) error {
+	tms := opts.TileMatrixSet
+	var code int
+	if _, err := fmt.Sscanf(tms.SRS, "EPSG:%d", &code); err != nil {
+		return fmt.Errorf("error: GeoPackage tiles need an EPSG tile matrix set SRS, got %q", tms.SRS)
+	}
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS gpkg_tile_matrix_set (
+			table_name TEXT NOT NULL PRIMARY KEY, srs_id INTEGER NOT NULL,
+			min_x DOUBLE NOT NULL, min_y DOUBLE NOT NULL, max_x DOUBLE NOT NULL, max_y DOUBLE NOT NULL,
+			CONSTRAINT fk_gtms_table_name FOREIGN KEY (table_name) REFERENCES gpkg_contents(table_name),
+			CONSTRAINT fk_gtms_srs FOREIGN KEY (srs_id) REFERENCES gpkg_spatial_ref_sys (srs_id))`,
+		`CREATE TABLE IF NOT EXISTS gpkg_tile_matrix (
+			table_name TEXT NOT NULL, zoom_level INTEGER NOT NULL,
+			matrix_width INTEGER NOT NULL, matrix_height INTEGER NOT NULL,
+			tile_width INTEGER NOT NULL, tile_height INTEGER NOT NULL,
+			pixel_x_size DOUBLE NOT NULL, pixel_y_size DOUBLE NOT NULL,
+			CONSTRAINT pk_ttm PRIMARY KEY (table_name, zoom_level),
+			CONSTRAINT fk_tmm_table_name FOREIGN KEY (table_name) REFERENCES gpkg_contents(table_name))`,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT, zoom_level INTEGER NOT NULL,
+			tile_column INTEGER NOT NULL, tile_row INTEGER NOT NULL, tile_data BLOB NOT NULL,
+			UNIQUE (zoom_level, tile_column, tile_row))`, sqlIdentifier(sink.Table)),
+		fmt.Sprintf(`INSERT OR REPLACE INTO gpkg_contents
+			(table_name, data_type, identifier, min_x, min_y, max_x, max_y, srs_id)
+			VALUES (%s, 'tiles', %s, %s, %s, %s, %s, %d)`,
+			sqlQuote(sink.Table), sqlQuote(sink.Table),
+			formatFloat(minX), formatFloat(minY), formatFloat(maxX), formatFloat(maxY), code),
+		fmt.Sprintf(`INSERT OR REPLACE INTO gpkg_tile_matrix_set
+			(table_name, srs_id, min_x, min_y, max_x, max_y) VALUES (%s, %d, %s, %s, %s, %s)`,
+			sqlQuote(sink.Table), code,
+			formatFloat(tms.MinX), formatFloat(tms.MinY), formatFloat(tms.MaxX), formatFloat(tms.MaxY)),
+	}
+	for zoom := opts.MinZoom; zoom <= opts.MaxZoom; zoom++ {
+		width, height := tms.MatrixSize(zoom)
+		resolution := formatFloat(tms.Resolution(zoom))
+		statements = append(statements, fmt.Sprintf(`INSERT OR REPLACE INTO gpkg_tile_matrix
+			(table_name, zoom_level, matrix_width, matrix_height, tile_width, tile_height, pixel_x_size, pixel_y_size)
+			VALUES (%s, %d, %d, %d, %d, %d, %s, %s)`,
+			sqlQuote(sink.Table), zoom, width, height, tms.TileSize, tms.TileSize, resolution, resolution))
+	}
+
+	if err := sink.registerSRS(code); err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		if err := execSQL(sink.ds, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerSRS adds EPSG:code to gpkg_spatial_ref_sys when missing.
+func (sink *GPKGTileSink) registerSRS(code int) error {
+	count, err := queryInt(sink.ds, fmt.Sprintf("SELECT COUNT(*) FROM gpkg_spatial_ref_sys WHERE srs_id = %d", code))
+	if err != nil || count > 0 {
+		return err
+	}
+
+	sr := CreateSpatialReference("")
+	defer sr.Destroy()
+	if err := sr.FromEPSG(code); err != nil {
+		return err
+	}
+	wkt, err := sr.ToWKT()
+	if err != nil {
+		return err
+	}
+	return execSQL(sink.ds, fmt.Sprintf(`INSERT INTO gpkg_spatial_ref_sys
+		(srs_name, srs_id, organization, organization_coordsys_id, definition)
+		VALUES (%s, %d, 'EPSG', %d, %s)`,
+		sqlQuote(fmt.Sprintf("EPSG:%d", code)), code, code, sqlQuote(wkt)))
+}
+
+// HasTile implements TileSink.
+func (sink *GPKGTileSink) HasTile(tile Tile) (bool, error) {
+	return sink.tiles.HasTile(tile.Zoom, tile.Col, tile.Row)
+}
+
+// WriteTile implements TileSink.
+func (sink *GPKGTileSink) WriteTile(tile Tile, data []byte) error {
+	return sink.tiles.WriteTile(tile.Zoom, tile.Col, tile.Row, data)
+}
+
+// Close implements TileSink.
+func (sink *GPKGTileSink) Close() error {
+	return sink.tiles.Close()
+}
@@ -0,0 +1,115 @@
+package gdal
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestMBTilesSink(t *testing.T) {
+	src := createTileSource(t, "./tmp/mbtiles_source.tif")
+	defer src.Close()
+
+	filename := "./tmp/tiles.mbtiles"
+	os.Remove(filename)
+	sink, err := NewMBTilesSink(filename, "test")
+	if err != nil {
+		t.Fatalf("NewMBTilesSink: %v", err)
+	}
+	opts := TileOptions{MinZoom: 10, MaxZoom: 12}
+	if err := GenerateTiles(src, sink, opts, nil, nil); err != nil {
+		sink.Close()
+		t.Fatalf("GenerateTiles: %v", err)
+	}
+	exists, err := sink.HasTile(Tile{Zoom: 12, Col: 2048, Row: 2048})
+	if err != nil || !exists {
+		t.Errorf("HasTile = %v, %v, want true", exists, err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	ds, err := Open(filename, ReadOnly)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer ds.Close()
+	if got := ds.Driver().ShortName(); got != DriverNameMBTiles {
+		t.Errorf("driver = %s, want %s", got, DriverNameMBTiles)
+	}
+	if ds.RasterXSize() == 0 || ds.RasterCount() != 4 {
+		t.Errorf("raster = %dx%d with %d bands", ds.RasterXSize(), ds.RasterYSize(), ds.RasterCount())
+	}
+
+	if _, err := NewMBTilesSink(filename, "test"); err != nil {
+		t.Errorf("reopening MBTiles: %v", err)
+	}
+}
+
+func TestMBTilesSinkLargeTiles(t *testing.T) {
+	filename := "./tmp/large_tiles.mbtiles"
+	os.Remove(filename)
+	sink, err := NewMBTilesSink(filename, "large")
+	if err != nil {
+		t.Fatalf("NewMBTilesSink: %v", err)
+	}
+	data := bytes.Repeat([]byte{0xAB}, 2<<20)
+	for col := 0; col < tileBatchSize+1; col++ {
+		if err := sink.WriteTile(Tile{Zoom: 9, Col: col, Row: 0}, data[:col+1]); err != nil {
+			sink.Close()
+			t.Fatalf("WriteTile(%d): %v", col, err)
+		}
+	}
+	if err := sink.WriteTile(Tile{Zoom: 9, Col: 0, Row: 0}, data); err != nil {
+		sink.Close()
+		t.Fatalf("WriteTile(large): %v", err)
+	}
+	if exists, err := sink.HasTile(Tile{Zoom: 9, Col: tileBatchSize, Row: 0}); err != nil || !exists {
+		t.Errorf("HasTile before Close = %v, %v, want true", exists, err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	ds, ok := OGRDriverByName(OGRDriverNameSQLite).Open(filename, 0)
+	if !ok {
+		t.Fatalf("failed to open %s", filename)
+	}
+	defer ds.Destroy()
+	if count, err := queryInt(ds, "SELECT COUNT(*) FROM tiles"); err != nil || count != tileBatchSize+1 {
+		t.Errorf("tile count = %d, %v, want %d", count, err, tileBatchSize+1)
+	}
+	if size, err := queryInt(ds, "SELECT length(tile_data) FROM tiles WHERE tile_column = 0"); err != nil || size != len(data) {
+		t.Errorf("large tile size = %d, %v, want %d", size, err, len(data))
+	}
+	if count, err := queryInt(ds, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('metadata', 'tiles')"); err != nil || count != 0 {
+		t.Errorf("%d unexpected tables, %v", count, err)
+	}
+}
+
+func TestGPKGTileSink(t *testing.T) {
+	src := createTileSource(t, "./tmp/gpkg_tiles_source.tif")
+	defer src.Close()
+
+	filename := "./tmp/tiles_sink.gpkg"
+	os.Remove(filename)
+	sink, err := NewGPKGTileSink(filename, "pyramid")
+	if err != nil {
+		t.Fatalf("NewGPKGTileSink: %v", err)
+	}
+	opts := TileOptions{MinZoom: 10, MaxZoom: 12}
+	if err := GenerateTiles(src, sink, opts, nil, nil); err != nil {
+		sink.Close()
+		t.Fatalf("GenerateTiles: %v", err)
+	}
+	sink.Close()
+
+	ds, err := OpenEx(filename, OFReadOnly|OFRaster, nil, []string{"TABLE=pyramid"}, nil)
+	if err != nil {
+		t.Fatalf("OpenEx: %v", err)
+	}
+	defer ds.Close()
+	if ds.RasterXSize() == 0 || ds.RasterCount() != 4 {
+		t.Errorf("raster = %dx%d with %d bands", ds.RasterXSize(), ds.RasterYSize(), ds.RasterCount())
+	}
+}
@@ -0,0 +1,186 @@
+package gdal
+
+/*
+#include "go_gdal.h"
+#include "gdal_version.h"
+#include "go_ogr_capabilities.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// ODsCCreateLayer and related constants are dataset capabilities for
+// Dataset.TestCapability.
+const (
+	ODsCCreateLayer                     = string(C.ODsCCreateLayer)
+	ODsCDeleteLayer                     = string(C.ODsCDeleteLayer)
+	ODsCCreateGeomFieldAfterCreateLayer = string(C.ODsCCreateGeomFieldAfterCreateLayer)
+	ODsCCurveGeometries                 = string(C.ODsCCurveGeometries)
+	ODsCTransactions                    = string(C.ODsCTransactions)
+	ODsCEmulatedTransactions            = string(C.ODsCEmulatedTransactions)
+	ODsCMeasuredGeometries              = string(C.ODsCMeasuredGeometries)
+	ODsCZGeometries                     = string(C.ODsCZGeometries)
+	ODsCRandomLayerRead                 = string(C.ODsCRandomLayerRead)
+	ODsCRandomLayerWrite                = string(C.ODsCRandomLayerWrite)
+	ODsCAddFieldDomain                  = string(C.ODsCAddFieldDomain)
+	ODsCDeleteFieldDomain               = string(C.ODsCDeleteFieldDomain)
+	ODsCUpdateFieldDomain               = string(C.ODsCUpdateFieldDomain)
+)
+
+// OLCRandomRead and related constants are layer capabilities for
+// Layer.TestCapability.
+const (
+	OLCRandomRead          = string(C.OLCRandomRead)
+	OLCSequentialWrite     = string(C.OLCSequentialWrite)
+	OLCRandomWrite         = string(C.OLCRandomWrite)
+	OLCFastSpatialFilter   = string(C.OLCFastSpatialFilter)
+	OLCFastFeatureCount    = string(C.OLCFastFeatureCount)
+	OLCFastGetExtent       = string(C.OLCFastGetExtent)
+	OLCFastSetNextByIndex  = string(C.OLCFastSetNextByIndex)
+	OLCCreateField         = string(C.OLCCreateField)
+	OLCCreateGeomField     = string(C.OLCCreateGeomField)
+	OLCDeleteField         = string(C.OLCDeleteField)
+	OLCReorderFields       = string(C.OLCReorderFields)
+	OLCAlterFieldDefn      = string(C.OLCAlterFieldDefn)
+	OLCAlterGeomFieldDefn  = string(C.OLCAlterGeomFieldDefn)
+	OLCTransactions        = string(C.OLCTransactions)
+	OLCDeleteFeature       = string(C.OLCDeleteFeature)
+	OLCUpsertFeature       = string(C.OLCUpsertFeature)
+	OLCStringsAsUTF8       = string(C.OLCStringsAsUTF8)
+	OLCIgnoreFields        = string(C.OLCIgnoreFields)
+	OLCCurveGeometries     = string(C.OLCCurveGeometries)
+	OLCMeasuredGeometries  = string(C.OLCMeasuredGeometries)
+	OLCZGeometries         = string(C.OLCZGeometries)
+	OLCFastGetArrowStream  = string(C.OLCFastGetArrowStream)
+	OLCFastWriteArrowBatch = string(C.OLCFastWriteArrowBatch)
+)
+
+// LayerCount returns the number of vector layers in the dataset.
+func (dataset Dataset) LayerCount() int {
+	return int(C.GDALDatasetGetLayerCount(dataset.cval))
+}
+
+// Layer returns the vector layer at index, counted from 0.
+func (dataset Dataset) Layer(index int) Layer {
+	return Layer{C.GDALDatasetGetLayer(dataset.cval, C.int(index))}
+}
+
+// LayerByName returns the vector layer named name.
+func (dataset Dataset) LayerByName(name string) Layer {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	return Layer{C.GDALDatasetGetLayerByName(dataset.cval, cName)}
+}
+
+// Layers returns all vector layers of the dataset.
+func (dataset Dataset) Layers() []Layer {
+	layers := make([]Layer, dataset.LayerCount())
+	for i := range layers {
+		layers[i] = dataset.Layer(i)
+	}
+	return layers
+}
+
+// CreateLayer creates a new vector layer on the dataset.
+func (dataset Dataset) CreateLayer(
+	name string,
+	sr SpatialReference,
+	geomType GeometryType,
+	options []string,
+) (Layer, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	layer := C.GDALDatasetCreateLayer(
+		dataset.cval,
+		cName,
+		sr.cval,
+		C.OGRwkbGeometryType(geomType),
+		(**C.char)(unsafe.Pointer(&opts[0])),
+	)
+	if layer == nil {
+		return Layer{}, fmt.Errorf("error: failed to create layer %q", name)
+	}
+	return Layer{layer}, nil
+}
+
+// CopyLayer duplicates source, possibly from another dataset, into a new
+// layer named name.
+func (dataset Dataset) CopyLayer(source Layer, name string, options []string) (Layer, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	length := len(options)
+	opts := make([]*C.char, length+1)
+	for i := 0; i < length; i++ {
+		opts[i] = C.CString(options[i])
+		defer C.free(unsafe.Pointer(opts[i]))
+	}
+	opts[length] = (*C.char)(unsafe.Pointer(nil))
+
+	layer := C.GDALDatasetCopyLayer(
+		dataset.cval,
+		source.cval,
+		cName,
+		(**C.char)(unsafe.Pointer(&opts[0])),
+	)
+	if layer == nil {
+		return Layer{}, fmt.Errorf("error: failed to copy layer to %q", name)
+	}
+	return Layer{layer}, nil
+}
+
+// DeleteLayer deletes the vector layer at index.
+func (dataset Dataset) DeleteLayer(index int) error {
+	return ErrFromOGRErr(C.GDALDatasetDeleteLayer(dataset.cval, C.int(index)))
+}
+
+// ExecuteSQL executes an SQL statement against the dataset. The returned
+// layer, if any, must be released with ReleaseResultSet.
+func (dataset Dataset) ExecuteSQL(sql string, filter Geometry, dialect string) Layer {
+	cSQL := C.CString(sql)
+	defer C.free(unsafe.Pointer(cSQL))
+	cDialect := C.CString(dialect)
+	defer C.free(unsafe.Pointer(cDialect))
+
+	return Layer{C.GDALDatasetExecuteSQL(dataset.cval, cSQL, filter.cval, cDialect)}
+}
+
+// ReleaseResultSet releases a layer returned by ExecuteSQL.
+func (dataset Dataset) ReleaseResultSet(layer Layer) {
+	C.GDALDatasetReleaseResultSet(dataset.cval, layer.cval)
+}
+
+// TestCapability reports whether the dataset has the indicated capability,
+// such as ODsCCreateLayer or ODsCTransactions.
+func (dataset Dataset) TestCapability(capability string) bool {
+	cCapability := C.CString(capability)
+	defer C.free(unsafe.Pointer(cCapability))
+	return C.GDALDatasetTestCapability(dataset.cval, cCapability) != 0
+}
+
+// StartTransaction starts a transaction on the dataset. When force is true,
+// drivers without native transactions emulate them, possibly slowly.
+func (dataset Dataset) StartTransaction(force bool) error {
+	return ErrFromOGRErr(C.GDALDatasetStartTransaction(dataset.cval, BoolToCInt(force)))
+}
+
+// CommitTransaction commits the current transaction.
+func (dataset Dataset) CommitTransaction() error {
+	return ErrFromOGRErr(C.GDALDatasetCommitTransaction(dataset.cval))
+}
+
+// RollbackTransaction rolls back the current transaction.
+func (dataset Dataset) RollbackTransaction() error {
+	return ErrFromOGRErr(C.GDALDatasetRollbackTransaction(dataset.cval))
+}
@@ -0,0 +1,119 @@
+package gdal
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDatasetVectorAPI(t *testing.T) {
+	filename := "./tmp/vector.gpkg"
+	os.Remove(filename)
+
+	driver, err := GetDriverByName(DriverNameGPKG)
+	if err != nil {
+		t.Fatalf("GetDriverByName(GPKG): %v", err)
+	}
+	ds := driver.Create(filename, 0, 0, 0, Unknown, nil)
+	if ds.cval == nil {
+		t.Fatal("GPKG Create returned nil dataset")
+	}
+
+	if !ds.TestCapability(ODsCCreateLayer) || !ds.TestCapability(ODsCTransactions) {
+		t.Error("GPKG should support layer creation and transactions")
+	}
+
+	sr := createSpatialReferenceFromEPSG(t, 4326)
+	defer sr.Destroy()
+	layer, err := ds.CreateLayer("points", sr, GT_Point, nil)
+	if err != nil {
+		t.Fatalf("CreateLayer: %v", err)
+	}
+	addLayerField(t, layer, "value", FT_Integer)
+
+	addFeatures := func(count int) {
+		for i := 0; i < count; i++ {
+			feature := layer.Definition().Create()
+			feature.SetFieldInteger(0, i)
+			if err := layer.Create(feature); err != nil {
+				t.Fatalf("Layer.Create: %v", err)
+			}
+			feature.Destroy()
+		}
+	}
+
+	if err := ds.StartTransaction(false); err != nil {
+		t.Fatalf("StartTransaction: %v", err)
+	}
+	addFeatures(3)
+	if err := ds.CommitTransaction(); err != nil {
+		t.Fatalf("CommitTransaction: %v", err)
+	}
+	if err := ds.StartTransaction(false); err != nil {
+		t.Fatalf("StartTransaction: %v", err)
+	}
+	addFeatures(2)
+	if err := ds.RollbackTransaction(); err != nil {
+		t.Fatalf("RollbackTransaction: %v", err)
+	}
+
+	result := ds.ExecuteSQL("SELECT COUNT(*) FROM points", Geometry{}, "")
+	if result.cval == nil {
+		t.Fatal("ExecuteSQL returned no layer")
+	}
+	feature := result.NextFeature()
+	if feature == nil || feature.FieldAsInteger(0) != 3 {
+		t.Errorf("feature count after rollback is not 3")
+	}
+	if feature != nil {
+		feature.Destroy()
+	}
+	ds.ReleaseResultSet(result)
+
+	if _, err := ds.CopyLayer(layer, "copy", nil); err != nil {
+		t.Fatalf("CopyLayer: %v", err)
+	}
+	if got := ds.LayerCount(); got != 2 {
+		t.Errorf("LayerCount() = %d, want 2", got)
+	}
+	if count, _ := ds.LayerByName("copy").FeatureCount(true); count != 3 {
+		t.Errorf("copied layer has %d features, want 3", count)
+	}
+	if err := ds.DeleteLayer(1); err != nil {
+		t.Fatalf("DeleteLayer: %v", err)
+	}
+	if layers := ds.Layers(); len(layers) != 1 || layers[0].Name() != "points" {
+		t.Errorf("layers after DeleteLayer = %d", len(layers))
+	}
+	ds.Close()
+
+	reopened, err := OpenEx(filename, OFVector, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("OpenEx: %v", err)
+	}
+	defer reopened.Close()
+	if reopened.LayerByName("points").cval == nil {
+		t.Error("layer points not found after reopening")
+	}
+	if reopened.Layer(0).Name() != "points" {
+		t.Errorf("Layer(0).Name() = %q", reopened.Layer(0).Name())
+	}
+}
+
+func TestDatasetMixedRasterVector(t *testing.T) {
+	filename := "/vsimem/mixed.gpkg"
+	createGPKGRasterTable(t, filename, "raster", false)
+	defer VSIUnlink(filename)
+
+	ds, err := OpenEx(filename, OFRaster|OFVector|OFUpdate, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("OpenEx: %v", err)
+	}
+	defer ds.Close()
+
+	if _, err := ds.CreateLayer("features", SpatialReference{}, GT_Point, nil); err != nil {
+		t.Fatalf("CreateLayer: %v", err)
+	}
+	if ds.RasterCount() == 0 || ds.LayerCount() != 1 {
+		t.Errorf("got %d bands and %d layers, want bands and 1 layer", ds.RasterCount(), ds.LayerCount())
+	}
+}
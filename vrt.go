@@ -0,0 +1,918 @@
+package gdal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VRTSourceKind selects the VRT source element type.
+type VRTSourceKind int
+
+const (
+	// VRTSimpleSource copies source pixels unchanged.
+	VRTSimpleSource VRTSourceKind = iota
+	// VRTComplexSource supports nodata, scaling and lookup tables.
+	VRTComplexSource
+	// VRTAveragedSource averages source pixels when downsampling.
+	VRTAveragedSource
+)
+
+var vrtSourceElements = map[VRTSourceKind]string{
+	VRTSimpleSource:   "SimpleSource",
+	VRTComplexSource:  "ComplexSource",
+	VRTAveragedSource: "AveragedSource",
+}
+
+// VRTSource is a source of a VRT band.
+type VRTSource struct {
+	Kind VRTSourceKind
+	// Filename is the source dataset name. It must be openable by GDAL,
+	// which includes /vsimem/ files.
+	Filename string
+	// RelativeToVRT reports whether Filename is relative to the VRT file.
+	RelativeToVRT bool
+	// Shared selects whether the source dataset handle is shared with other
+	// sources. Nil leaves the GDAL default.
+	Shared *bool
+	// Band is the 1-based source band.
+	Band int
+	// SrcWindow and DstWindow select the source and destination regions.
+	// A zero window covers the whole raster.
+	SrcWindow, DstWindow Window
+	// Resampling is the resampling method used when window sizes differ.
+	Resampling string
+	// NoData is the source nodata value of a complex source.
+	NoData *float64
+	// ScaleOffset and ScaleRatio are applied by a complex source as
+	// value*ScaleRatio + ScaleOffset. A zero ScaleRatio leaves values unscaled.
+	ScaleOffset, ScaleRatio float64
+	// LUT maps source values to output values by linear interpolation in a
+	// complex source.
+	LUT [][2]float64
+	// ExtraXML holds source elements the builder does not model, such as
+	// SourceProperties or UseMaskBand, as raw XML written back unchanged.
+	ExtraXML string
+}
+
+// VRTBand is a band of a VRTBuilder.
+type VRTBand struct {
+	DataType    DataType
+	Description string
+	NoData      *float64
+	// BlockXSize and BlockYSize set the band block size. Zero leaves the
+	// GDAL default.
+	BlockXSize, BlockYSize int
+	Sources                []*VRTSource
+	// PixelFunction makes the band a derived band computed by the named
+	// pixel function. PixelFunctionArguments are passed to it.
+	PixelFunction          string
+	PixelFunctionArguments map[string]string
+	// SourceTransferType is the data type sources are read with before the
+	// pixel function is applied.
+	SourceTransferType DataType
+	// ExtraXML holds band elements the builder does not model, such as
+	// ColorInterp, Metadata, Offset or Scale, as raw XML written back
+	// unchanged.
+	ExtraXML string
+	// ExtraAttrs holds band attributes the builder does not model, written
+	// back unchanged.
+	ExtraAttrs map[string]string
+}
+
+// VRTWarpOptions are the warp options of a warped VRT (VRTWarpedDataset).
+type VRTWarpOptions struct {
+	// SourceDataset is the name of the dataset being warped.
+	SourceDataset string
+	// RelativeToVRT reports whether SourceDataset is relative to the VRT
+	// file.
+	RelativeToVRT bool
+	// Resampling is the GDAL resampling name, such as NearestNeighbour or
+	// Bilinear.
+	Resampling string
+	// WorkingDataType is the data type pixels are warped in.
+	WorkingDataType DataType
+	// WarpMemoryLimit is the warp working memory in bytes. Zero leaves the
+	// GDAL default.
+	WarpMemoryLimit float64
+	// Options are warp options such as INIT_DEST.
+	Options map[string]string
+	// BandMapping maps source bands onto bands of the warped VRT.
+	BandMapping []VRTWarpBand
+	// SrcAlphaBand and DstAlphaBand are the 1-based alpha bands of the
+	// source and of the warped VRT, zero if there is none.
+	SrcAlphaBand, DstAlphaBand int
+	// ExtraXML holds warp option elements the builder does not model, such
+	// as the Transformer GDAL requires or a Cutline, as raw XML written back
+	// unchanged.
+	ExtraXML string
+}
+
+// VRTWarpBand maps a source band onto a band of a warped VRT.
+type VRTWarpBand struct {
+	// Src and Dst are the 1-based source and warped VRT bands.
+	Src, Dst int
+	// SrcNoData and DstNoData are the nodata values used while warping.
+	SrcNoData, DstNoData *float64
+}
+
+// VRTBuilder builds VRT datasets.
+type VRTBuilder struct {
+	XSize, YSize int
+	// SRS is the spatial reference in WKT or any form GDAL accepts.
+	SRS string
+	// SRSAxisMapping is the data axis to SRS axis mapping, such as [2 1]
+	// for a geographic SRS with longitude first. Nil leaves the GDAL
+	// default.
+	SRSAxisMapping []int
+	// SRSCoordinateEpoch is the coordinate epoch of a dynamic SRS, zero if
+	// unset.
+	SRSCoordinateEpoch float64
+	// GeoTransform is the affine transform, omitted when nil.
+	GeoTransform *[6]float64
+	Bands        []*VRTBand
+	// BlockXSize and BlockYSize set the dataset block size, as used by
+	// warped VRTs. Zero leaves the GDAL default.
+	BlockXSize, BlockYSize int
+	// Warp makes the VRT a warped VRT reading its bands from
+	// Warp.SourceDataset. The bands of a warped VRT have no sources.
+	Warp *VRTWarpOptions
+	// ExtraXML holds dataset elements the builder does not model, such as
+	// Metadata or MaskBand, as raw XML written back unchanged.
+	ExtraXML string
+}
+
+// NewVRTBuilder returns a builder for a VRT of the given size.
+func NewVRTBuilder(xSize, ySize int) *VRTBuilder {
+	return &VRTBuilder{XSize: xSize, YSize: ySize}
+}
+
+// NewVRTBuilderFromDataset returns a builder with the size, spatial reference
+// and geotransform of dataset.
+func NewVRTBuilderFromDataset(dataset Dataset) *VRTBuilder {
+	gt := dataset.GeoTransform()
+	return &VRTBuilder{
+		XSize:        dataset.RasterXSize(),
+		YSize:        dataset.RasterYSize(),
+		SRS:          dataset.Projection(),
+		GeoTransform: &gt,
+	}
+}
+
+// AddBand appends a band of the given data type.
+func (builder *VRTBuilder) AddBand(dataType DataType) *VRTBand {
+	band := &VRTBand{DataType: dataType}
+	builder.Bands = append(builder.Bands, band)
+	return band
+}
+
+// AddDerivedBand appends a band computed by the named pixel function.
+func (builder *VRTBuilder) AddDerivedBand(
+	dataType DataType,
+	pixelFunction string,
+	arguments map[string]string,
+) *VRTBand {
+	band := builder.AddBand(dataType)
+	band.PixelFunction = pixelFunction
+	band.PixelFunctionArguments = arguments
+	return band
+}
+
+// AddSimpleSource adds src as a simple source mapping srcWindow onto dstWindow.
+func (band *VRTBand) AddSimpleSource(src RasterBand, srcWindow, dstWindow Window) (*VRTSource, error) {
+	return band.addSource(VRTSimpleSource, src, srcWindow, dstWindow)
+}
+
+// AddComplexSource adds src as a complex source mapping srcWindow onto
+// dstWindow. Its nodata value, if any, is used as the source nodata.
+func (band *VRTBand) AddComplexSource(src RasterBand, srcWindow, dstWindow Window) (*VRTSource, error) {
+	source, err := band.addSource(VRTComplexSource, src, srcWindow, dstWindow)
+	if err != nil {
+		return nil, err
+	}
+	if noData, ok := src.NoDataValue(); ok {
+		source.NoData = &noData
+	}
+	return source, nil
+}
+
+// AddAveragedSource adds src as an averaged source mapping srcWindow onto
+// dstWindow.
+func (band *VRTBand) AddAveragedSource(src RasterBand, srcWindow, dstWindow Window) (*VRTSource, error) {
+	return band.addSource(VRTAveragedSource, src, srcWindow, dstWindow)
+}
+
+func (band *VRTBand) addSource(kind VRTSourceKind, src RasterBand, srcWindow, dstWindow Window) (*VRTSource, error) {
+	dataset := src.GetDataset()
+	if dataset.cval == nil || dataset.Description() == "" {
+		return nil, fmt.Errorf("vrt source is not backed by a file")
+	}
+	source := &VRTSource{
+		Kind:      kind,
+		Filename:  dataset.Description(),
+		Band:      src.BandNumber(),
+		SrcWindow: srcWindow,
+		DstWindow: dstWindow,
+	}
+	band.Sources = append(band.Sources, source)
+	return source, nil
+}
+
+// Build opens the VRT through the VRT driver. The caller is responsible for
+// closing the returned dataset.
+func (builder *VRTBuilder) Build() (Dataset, error) {
+	text, err := builder.XML()
+	if err != nil {
+		return Dataset{}, err
+	}
+	return OpenEx(text, OFRaster|OFReadOnly, []string{DriverNameVRT}, nil, nil)
+}
+
+// XML serializes the VRT.
+func (builder *VRTBuilder) XML() (string, error) {
+	if builder.XSize <= 0 || builder.YSize <= 0 {
+		return "", fmt.Errorf("vrt size %dx%d is invalid", builder.XSize, builder.YSize)
+	}
+
+	doc := vrtDatasetXML{
+		XSize:      builder.XSize,
+		YSize:      builder.YSize,
+		BlockXSize: builder.BlockXSize,
+		BlockYSize: builder.BlockYSize,
+	}
+	if builder.SRS != "" || builder.SRSAxisMapping != nil || builder.SRSCoordinateEpoch != 0 {
+		doc.SRS = &vrtSRSXML{Value: builder.SRS}
+		if builder.SRSAxisMapping != nil {
+			axes := make([]string, len(builder.SRSAxisMapping))
+			for i, axis := range builder.SRSAxisMapping {
+				axes[i] = strconv.Itoa(axis)
+			}
+			doc.SRS.AxisMapping = strings.Join(axes, ",")
+		}
+		if builder.SRSCoordinateEpoch != 0 {
+			doc.SRS.CoordinateEpoch = formatFloat(builder.SRSCoordinateEpoch)
+		}
+	}
+	if builder.Warp != nil {
+		doc.SubClass = "VRTWarpedDataset"
+		warp, err := builder.Warp.toXML()
+		if err != nil {
+			return "", err
+		}
+		doc.Warp = warp
+	}
+	if gt := builder.GeoTransform; gt != nil {
+		values := make([]string, len(gt))
+		for i, value := range gt {
+			values[i] = formatFloat(value)
+		}
+		doc.GeoTransform = strings.Join(values, ", ")
+	}
+	extra, err := parseVRTRawXML(builder.ExtraXML)
+	if err != nil {
+		return "", fmt.Errorf("vrt extra XML is invalid: %v", err)
+	}
+	doc.Other = extra
+
+	for i, band := range builder.Bands {
+		bandXML := vrtBandXML{
+			DataType:          band.DataType.Name(),
+			Band:              i + 1,
+			BlockXSize:        band.BlockXSize,
+			BlockYSize:        band.BlockYSize,
+			Description:       band.Description,
+			PixelFunctionType: band.PixelFunction,
+			Attrs:             sortedXMLAttrs(band.ExtraAttrs),
+		}
+		if band.DataType == Unknown {
+			return "", fmt.Errorf("vrt band %d has no data type", i+1)
+		}
+		if builder.Warp != nil {
+			if len(band.Sources) > 0 || band.PixelFunction != "" {
+				return "", fmt.Errorf("vrt band %d of a warped vrt cannot have sources or a pixel function", i+1)
+			}
+			bandXML.SubClass = "VRTWarpedRasterBand"
+		}
+		if band.NoData != nil {
+			bandXML.NoData = formatVRTFloat(*band.NoData)
+		}
+		if band.PixelFunction != "" {
+			bandXML.SubClass = "VRTDerivedRasterBand"
+			if band.SourceTransferType != Unknown {
+				bandXML.SourceTransferType = band.SourceTransferType.Name()
+			}
+			if len(band.PixelFunctionArguments) > 0 {
+				bandXML.PixelFunctionArguments = &vrtArgumentsXML{
+					Attrs: sortedXMLAttrs(band.PixelFunctionArguments),
+				}
+			}
+		}
+
+		for _, source := range band.Sources {
+			element, ok := vrtSourceElements[source.Kind]
+			if !ok {
+				return "", fmt.Errorf("vrt band %d has an unknown source kind %d", i+1, source.Kind)
+			}
+			sourceXML := vrtSourceXML{
+				XMLName:        xml.Name{Local: element},
+				Resampling:     source.Resampling,
+				SourceFilename: vrtFilenameXML{Name: source.Filename},
+				SourceBand:     source.Band,
+				SrcRect:        newVRTRect(source.SrcWindow),
+				DstRect:        newVRTRect(source.DstWindow),
+			}
+			if source.RelativeToVRT {
+				sourceXML.SourceFilename.RelativeToVRT = 1
+			}
+			if source.Shared != nil {
+				sourceXML.SourceFilename.Shared = formatVRTBool(*source.Shared)
+			}
+			if source.Kind == VRTComplexSource {
+				if source.NoData != nil {
+					sourceXML.NoData = formatVRTFloat(*source.NoData)
+				}
+				if source.ScaleRatio != 0 {
+					sourceXML.ScaleOffset = formatFloat(source.ScaleOffset)
+					sourceXML.ScaleRatio = formatFloat(source.ScaleRatio)
+				}
+				if len(source.LUT) > 0 {
+					entries := make([]string, len(source.LUT))
+					for j, entry := range source.LUT {
+						entries[j] = formatFloat(entry[0]) + ":" + formatFloat(entry[1])
+					}
+					sourceXML.LUT = strings.Join(entries, ",")
+				}
+			} else if source.NoData != nil || source.ScaleRatio != 0 || len(source.LUT) > 0 {
+				return "", fmt.Errorf("vrt band %d: nodata, scaling and LUT require a complex source", i+1)
+			}
+			if sourceXML.Other, err = parseVRTRawXML(source.ExtraXML); err != nil {
+				return "", fmt.Errorf("vrt band %d source extra XML is invalid: %v", i+1, err)
+			}
+			raw, err := encodeVRTRawXML(sourceXML)
+			if err != nil {
+				return "", err
+			}
+			bandXML.Elements = append(bandXML.Elements, raw)
+		}
+		extra, err := parseVRTRawXML(band.ExtraXML)
+		if err != nil {
+			return "", fmt.Errorf("vrt band %d extra XML is invalid: %v", i+1, err)
+		}
+		bandXML.Elements = append(bandXML.Elements, extra...)
+		doc.Bands = append(doc.Bands, bandXML)
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// ParseVRT parses VRT XML, including warped VRTs, into a builder. Elements
+// the builder does not model are kept in the ExtraXML fields so that XML
+// writes them back. Other dataset and band subclasses, source kinds other
+// than simple, complex and averaged sources, source windows with fractional
+// offsets or sizes and unknown SRS attributes are rejected.
+func ParseVRT(text string) (*VRTBuilder, error) {
+	var doc vrtDatasetXML
+	if err := xml.Unmarshal([]byte(text), &doc); err != nil {
+		return nil, err
+	}
+	warped := doc.SubClass == "VRTWarpedDataset"
+	if doc.SubClass != "" && !warped {
+		return nil, fmt.Errorf("vrt subclass %q is not supported", doc.SubClass)
+	}
+	if warped != (doc.Warp != nil) {
+		return nil, fmt.Errorf("vrt GDALWarpOptions are only valid in a warped vrt")
+	}
+
+	builder := NewVRTBuilder(doc.XSize, doc.YSize)
+	builder.BlockXSize = doc.BlockXSize
+	builder.BlockYSize = doc.BlockYSize
+	if doc.SRS != nil {
+		if len(doc.SRS.Attrs) > 0 {
+			return nil, fmt.Errorf("vrt SRS attribute %q is not supported", doc.SRS.Attrs[0].Name.Local)
+		}
+		builder.SRS = strings.TrimSpace(doc.SRS.Value)
+		if doc.SRS.AxisMapping != "" {
+			for _, part := range strings.Split(doc.SRS.AxisMapping, ",") {
+				axis, err := strconv.Atoi(strings.TrimSpace(part))
+				if err != nil {
+					return nil, fmt.Errorf("vrt axis mapping %q is invalid", doc.SRS.AxisMapping)
+				}
+				builder.SRSAxisMapping = append(builder.SRSAxisMapping, axis)
+			}
+		}
+		if doc.SRS.CoordinateEpoch != "" {
+			epoch, err := parseVRTFloat(doc.SRS.CoordinateEpoch)
+			if err != nil {
+				return nil, err
+			}
+			builder.SRSCoordinateEpoch = epoch
+		}
+	}
+	if warped {
+		warp, err := doc.Warp.options()
+		if err != nil {
+			return nil, err
+		}
+		builder.Warp = warp
+	}
+	extra, err := formatVRTRawXML(doc.Other)
+	if err != nil {
+		return nil, err
+	}
+	builder.ExtraXML = extra
+	if doc.GeoTransform != "" {
+		parts := strings.Split(doc.GeoTransform, ",")
+		if len(parts) != 6 {
+			return nil, fmt.Errorf("vrt geotransform %q is invalid", doc.GeoTransform)
+		}
+		var gt [6]float64
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, fmt.Errorf("vrt geotransform %q is invalid", doc.GeoTransform)
+			}
+			gt[i] = value
+		}
+		builder.GeoTransform = &gt
+	}
+
+	sort.SliceStable(doc.Bands, func(i, j int) bool { return doc.Bands[i].Band < doc.Bands[j].Band })
+	for _, bandXML := range doc.Bands {
+		switch bandXML.SubClass {
+		case "", "VRTSourcedRasterBand", "VRTDerivedRasterBand":
+		case "VRTWarpedRasterBand":
+			if !warped {
+				return nil, fmt.Errorf("vrt band %d is a warped band outside a warped vrt", bandXML.Band)
+			}
+		default:
+			return nil, fmt.Errorf("vrt band subclass %q is not supported", bandXML.SubClass)
+		}
+		band := builder.AddBand(DataTypeByName(bandXML.DataType))
+		band.Description = bandXML.Description
+		band.BlockXSize = bandXML.BlockXSize
+		band.BlockYSize = bandXML.BlockYSize
+		if len(bandXML.Attrs) > 0 {
+			band.ExtraAttrs = map[string]string{}
+			for _, attr := range bandXML.Attrs {
+				band.ExtraAttrs[attr.Name.Local] = attr.Value
+			}
+		}
+		if bandXML.NoData != "" {
+			noData, err := parseVRTFloat(bandXML.NoData)
+			if err != nil {
+				return nil, err
+			}
+			band.NoData = &noData
+		}
+		band.PixelFunction = strings.TrimSpace(bandXML.PixelFunctionType)
+		if bandXML.SourceTransferType != "" {
+			band.SourceTransferType = DataTypeByName(bandXML.SourceTransferType)
+		}
+		if bandXML.PixelFunctionArguments != nil {
+			band.PixelFunctionArguments = map[string]string{}
+			for _, attr := range bandXML.PixelFunctionArguments.Attrs {
+				band.PixelFunctionArguments[attr.Name.Local] = attr.Value
+			}
+		}
+
+		var others []vrtRawXML
+		for _, element := range bandXML.Elements {
+			kind, ok := vrtSourceKindByElement(element.XMLName.Local)
+			if !ok {
+				if strings.HasSuffix(element.XMLName.Local, "Source") {
+					return nil, fmt.Errorf("vrt source %q is not supported", element.XMLName.Local)
+				}
+				others = append(others, element)
+				continue
+			}
+			var sourceXML vrtSourceXML
+			if err := decodeVRTRawXML(element, &sourceXML); err != nil {
+				return nil, err
+			}
+			srcWindow, err := sourceXML.SrcRect.window()
+			if err != nil {
+				return nil, err
+			}
+			dstWindow, err := sourceXML.DstRect.window()
+			if err != nil {
+				return nil, err
+			}
+			source := &VRTSource{
+				Kind:          kind,
+				Filename:      strings.TrimSpace(sourceXML.SourceFilename.Name),
+				RelativeToVRT: sourceXML.SourceFilename.RelativeToVRT != 0,
+				Band:          sourceXML.SourceBand,
+				Resampling:    sourceXML.Resampling,
+				SrcWindow:     srcWindow,
+				DstWindow:     dstWindow,
+			}
+			if sourceXML.SourceFilename.Shared != "" {
+				shared := parseVRTBool(sourceXML.SourceFilename.Shared)
+				source.Shared = &shared
+			}
+			if source.ExtraXML, err = formatVRTRawXML(sourceXML.Other); err != nil {
+				return nil, err
+			}
+			if sourceXML.NoData != "" {
+				noData, err := parseVRTFloat(sourceXML.NoData)
+				if err != nil {
+					return nil, err
+				}
+				source.NoData = &noData
+			}
+			if sourceXML.ScaleRatio != "" {
+				ratio, err := parseVRTFloat(sourceXML.ScaleRatio)
+				if err != nil {
+					return nil, err
+				}
+				source.ScaleRatio = ratio
+			}
+			if sourceXML.ScaleOffset != "" {
+				offset, err := parseVRTFloat(sourceXML.ScaleOffset)
+				if err != nil {
+					return nil, err
+				}
+				source.ScaleOffset = offset
+			}
+			if sourceXML.LUT != "" {
+				for _, entry := range strings.Split(sourceXML.LUT, ",") {
+					parts := strings.Split(entry, ":")
+					if len(parts) != 2 {
+						return nil, fmt.Errorf("vrt LUT entry %q is invalid", entry)
+					}
+					from, err := parseVRTFloat(parts[0])
+					if err != nil {
+						return nil, err
+					}
+					to, err := parseVRTFloat(parts[1])
+					if err != nil {
+						return nil, err
+					}
+					source.LUT = append(source.LUT, [2]float64{from, to})
+				}
+			}
+			band.Sources = append(band.Sources, source)
+		}
+		if warped && len(band.Sources) > 0 {
+			return nil, fmt.Errorf("vrt band %d of a warped vrt cannot have sources", bandXML.Band)
+		}
+		if band.ExtraXML, err = formatVRTRawXML(others); err != nil {
+			return nil, err
+		}
+	}
+	return builder, nil
+}
+
+// VRTXML returns the VRT XML description of a VRT dataset, including warped
+// VRTs created by AutoCreateWarpedVRT.
+func VRTXML(dataset Dataset) (string, error) {
+	items := dataset.Metadata("xml:VRT")
+	if len(items) == 0 {
+		return "", fmt.Errorf("dataset has no VRT description")
+	}
+	return items[0], nil
+}
+
+// BuildWarpedVRT returns a warped VRT reprojecting dataset to dstWKT. It is
+// a convenience around AutoCreateWarpedVRT; use VRTXML to serialize it and
+// ParseVRT to edit it.
+func BuildWarpedVRT(dataset Dataset, dstWKT string, resampleAlg ResampleAlg) (Dataset, error) {
+	return dataset.AutoCreateWarpedVRT(dataset.Projection(), dstWKT, resampleAlg)
+}
+
+type vrtDatasetXML struct {
+	XMLName      xml.Name           `xml:"VRTDataset"`
+	XSize        int                `xml:"rasterXSize,attr"`
+	YSize        int                `xml:"rasterYSize,attr"`
+	SubClass     string             `xml:"subClass,attr,omitempty"`
+	SRS          *vrtSRSXML         `xml:"SRS"`
+	GeoTransform string             `xml:"GeoTransform,omitempty"`
+	Bands        []vrtBandXML       `xml:"VRTRasterBand"`
+	BlockXSize   int                `xml:"BlockXSize,omitempty"`
+	BlockYSize   int                `xml:"BlockYSize,omitempty"`
+	Warp         *vrtWarpOptionsXML `xml:"GDALWarpOptions"`
+	Other        []vrtRawXML        `xml:",any"`
+}
+
+type vrtSRSXML struct {
+	AxisMapping     string     `xml:"dataAxisToSRSAxisMapping,attr,omitempty"`
+	CoordinateEpoch string     `xml:"coordinateEpoch,attr,omitempty"`
+	Attrs           []xml.Attr `xml:",any,attr"`
+	Value           string     `xml:",chardata"`
+}
+
+type vrtBandXML struct {
+	DataType               string           `xml:"dataType,attr"`
+	Band                   int              `xml:"band,attr"`
+	SubClass               string           `xml:"subClass,attr,omitempty"`
+	BlockXSize             int              `xml:"blockXSize,attr,omitempty"`
+	BlockYSize             int              `xml:"blockYSize,attr,omitempty"`
+	Attrs                  []xml.Attr       `xml:",any,attr"`
+	Description            string           `xml:"Description,omitempty"`
+	NoData                 string           `xml:"NoDataValue,omitempty"`
+	PixelFunctionType      string           `xml:"PixelFunctionType,omitempty"`
+	PixelFunctionArguments *vrtArgumentsXML `xml:"PixelFunctionArguments"`
+	SourceTransferType     string           `xml:"SourceTransferType,omitempty"`
+	Elements               []vrtRawXML      `xml:",any"`
+}
+
+type vrtArgumentsXML struct {
+	Attrs []xml.Attr `xml:",any,attr"`
+}
+
+type vrtSourceXML struct {
+	XMLName        xml.Name
+	Resampling     string         `xml:"resampling,attr,omitempty"`
+	SourceFilename vrtFilenameXML `xml:"SourceFilename"`
+	SourceBand     int            `xml:"SourceBand"`
+	SrcRect        *vrtRectXML    `xml:"SrcRect"`
+	DstRect        *vrtRectXML    `xml:"DstRect"`
+	NoData         string         `xml:"NODATA,omitempty"`
+	ScaleOffset    string         `xml:"ScaleOffset,omitempty"`
+	ScaleRatio     string         `xml:"ScaleRatio,omitempty"`
+	LUT            string         `xml:"LUT,omitempty"`
+	Other          []vrtRawXML    `xml:",any"`
+}
+
+// vrtRawXML is an element kept as raw XML so that it survives a round trip.
+type vrtRawXML struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   string     `xml:",innerxml"`
+}
+
+func parseVRTRawXML(text string) ([]vrtRawXML, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	var holder struct {
+		Elements []vrtRawXML `xml:",any"`
+	}
+	if err := xml.Unmarshal([]byte("<Extra>"+text+"</Extra>"), &holder); err != nil {
+		return nil, err
+	}
+	return holder.Elements, nil
+}
+
+func formatVRTRawXML(elements []vrtRawXML) (string, error) {
+	var text strings.Builder
+	for _, element := range elements {
+		out, err := xml.Marshal(element)
+		if err != nil {
+			return "", err
+		}
+		text.Write(out)
+	}
+	return text.String(), nil
+}
+
+func encodeVRTRawXML(v interface{}) (vrtRawXML, error) {
+	var element vrtRawXML
+	out, err := xml.Marshal(v)
+	if err != nil {
+		return element, err
+	}
+	err = xml.Unmarshal(out, &element)
+	return element, err
+}
+
+func decodeVRTRawXML(element vrtRawXML, v interface{}) error {
+	out, err := xml.Marshal(element)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(out, v)
+}
+
+type vrtFilenameXML struct {
+	RelativeToVRT int    `xml:"relativeToVRT,attr"`
+	Shared        string `xml:"shared,attr,omitempty"`
+	Name          string `xml:",chardata"`
+}
+
+type vrtWarpOptionsXML struct {
+	WarpMemoryLimit string              `xml:"WarpMemoryLimit,omitempty"`
+	ResampleAlg     string              `xml:"ResampleAlg,omitempty"`
+	WorkingDataType string              `xml:"WorkingDataType,omitempty"`
+	Options         []vrtOptionXML      `xml:"Option"`
+	SourceDataset   vrtFilenameXML      `xml:"SourceDataset"`
+	BandMapping     []vrtBandMappingXML `xml:"BandList>BandMapping"`
+	SrcAlphaBand    int                 `xml:"SrcAlphaBand,omitempty"`
+	DstAlphaBand    int                 `xml:"DstAlphaBand,omitempty"`
+	Other           []vrtRawXML         `xml:",any"`
+}
+
+type vrtOptionXML struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type vrtBandMappingXML struct {
+	Src           int    `xml:"src,attr"`
+	Dst           int    `xml:"dst,attr"`
+	SrcNoDataReal string `xml:"SrcNoDataReal,omitempty"`
+	SrcNoDataImag string `xml:"SrcNoDataImag,omitempty"`
+	DstNoDataReal string `xml:"DstNoDataReal,omitempty"`
+	DstNoDataImag string `xml:"DstNoDataImag,omitempty"`
+}
+
+func (warp *VRTWarpOptions) toXML() (*vrtWarpOptionsXML, error) {
+	if warp.SourceDataset == "" {
+		return nil, fmt.Errorf("warped vrt has no source dataset")
+	}
+	doc := &vrtWarpOptionsXML{
+		ResampleAlg:   warp.Resampling,
+		SourceDataset: vrtFilenameXML{Name: warp.SourceDataset},
+		SrcAlphaBand:  warp.SrcAlphaBand,
+		DstAlphaBand:  warp.DstAlphaBand,
+	}
+	if warp.RelativeToVRT {
+		doc.SourceDataset.RelativeToVRT = 1
+	}
+	if warp.WarpMemoryLimit != 0 {
+		doc.WarpMemoryLimit = formatFloat(warp.WarpMemoryLimit)
+	}
+	if warp.WorkingDataType != Unknown {
+		doc.WorkingDataType = warp.WorkingDataType.Name()
+	}
+	for _, attr := range sortedXMLAttrs(warp.Options) {
+		doc.Options = append(doc.Options, vrtOptionXML{Name: attr.Name.Local, Value: attr.Value})
+	}
+	for _, mapping := range warp.BandMapping {
+		mappingXML := vrtBandMappingXML{Src: mapping.Src, Dst: mapping.Dst}
+		if mapping.SrcNoData != nil {
+			mappingXML.SrcNoDataReal = formatVRTFloat(*mapping.SrcNoData)
+		}
+		if mapping.DstNoData != nil {
+			mappingXML.DstNoDataReal = formatVRTFloat(*mapping.DstNoData)
+		}
+		doc.BandMapping = append(doc.BandMapping, mappingXML)
+	}
+	other, err := parseVRTRawXML(warp.ExtraXML)
+	if err != nil {
+		return nil, fmt.Errorf("vrt warp extra XML is invalid: %v", err)
+	}
+	doc.Other = other
+	return doc, nil
+}
+
+func (doc *vrtWarpOptionsXML) options() (*VRTWarpOptions, error) {
+	warp := &VRTWarpOptions{
+		SourceDataset: strings.TrimSpace(doc.SourceDataset.Name),
+		RelativeToVRT: doc.SourceDataset.RelativeToVRT != 0,
+		Resampling:    strings.TrimSpace(doc.ResampleAlg),
+		SrcAlphaBand:  doc.SrcAlphaBand,
+		DstAlphaBand:  doc.DstAlphaBand,
+	}
+	if doc.WarpMemoryLimit != "" {
+		limit, err := parseVRTFloat(doc.WarpMemoryLimit)
+		if err != nil {
+			return nil, err
+		}
+		warp.WarpMemoryLimit = limit
+	}
+	if doc.WorkingDataType != "" {
+		warp.WorkingDataType = DataTypeByName(strings.TrimSpace(doc.WorkingDataType))
+	}
+	if len(doc.Options) > 0 {
+		warp.Options = map[string]string{}
+		for _, option := range doc.Options {
+			warp.Options[option.Name] = option.Value
+		}
+	}
+	for _, mappingXML := range doc.BandMapping {
+		for _, imag := range []string{mappingXML.SrcNoDataImag, mappingXML.DstNoDataImag} {
+			if imag == "" {
+				continue
+			}
+			if value, err := parseVRTFloat(imag); err != nil || value != 0 {
+				return nil, fmt.Errorf("vrt warp band %d has an imaginary nodata value", mappingXML.Dst)
+			}
+		}
+		mapping := VRTWarpBand{Src: mappingXML.Src, Dst: mappingXML.Dst}
+		if mappingXML.SrcNoDataReal != "" {
+			noData, err := parseVRTFloat(mappingXML.SrcNoDataReal)
+			if err != nil {
+				return nil, err
+			}
+			mapping.SrcNoData = &noData
+		}
+		if mappingXML.DstNoDataReal != "" {
+			noData, err := parseVRTFloat(mappingXML.DstNoDataReal)
+			if err != nil {
+				return nil, err
+			}
+			mapping.DstNoData = &noData
+		}
+		warp.BandMapping = append(warp.BandMapping, mapping)
+	}
+	extra, err := formatVRTRawXML(doc.Other)
+	if err != nil {
+		return nil, err
+	}
+	warp.ExtraXML = extra
+	return warp, nil
+}
+
+type vrtRectXML struct {
+	XOff  float64 `xml:"xOff,attr"`
+	YOff  float64 `xml:"yOff,attr"`
+	XSize float64 `xml:"xSize,attr"`
+	YSize float64 `xml:"ySize,attr"`
+}
+
+func newVRTRect(window Window) *vrtRectXML {
+	if window == (Window{}) {
+		return nil
+	}
+	return &vrtRectXML{
+		XOff:  float64(window.XOff),
+		YOff:  float64(window.YOff),
+		XSize: float64(window.XSize),
+		YSize: float64(window.YSize),
+	}
+}
+
+func (rect *vrtRectXML) window() (Window, error) {
+	if rect == nil {
+		return Window{}, nil
+	}
+	for _, value := range []float64{rect.XOff, rect.YOff, rect.XSize, rect.YSize} {
+		if value != math.Trunc(value) {
+			return Window{}, fmt.Errorf("vrt rectangle %v,%v %vx%v has fractional values",
+				rect.XOff, rect.YOff, rect.XSize, rect.YSize)
+		}
+	}
+	return Window{
+		XOff:  int(rect.XOff),
+		YOff:  int(rect.YOff),
+		XSize: int(rect.XSize),
+		YSize: int(rect.YSize),
+	}, nil
+}
+
+func vrtSourceKindByElement(element string) (VRTSourceKind, bool) {
+	for kind, name := range vrtSourceElements {
+		if name == element {
+			return kind, true
+		}
+	}
+	return 0, false
+}
+
+// sortedXMLAttrs returns values as attributes sorted by name.
+func sortedXMLAttrs(values map[string]string) []xml.Attr {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := make([]xml.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: key}, Value: values[key]})
+	}
+	return attrs
+}
+
+func formatVRTBool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// parseVRTBool follows CPLTestBool, which treats anything but NO, FALSE,
+// OFF and 0 as true.
+func parseVRTBool(text string) bool {
+	switch strings.ToUpper(strings.TrimSpace(text)) {
+	case "NO", "FALSE", "OFF", "0":
+		return false
+	}
+	return true
+}
+
+func formatVRTFloat(value float64) string {
+	if math.IsNaN(value) {
+		return "nan"
+	}
+	return formatFloat(value)
+}
+
+func parseVRTFloat(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if strings.EqualFold(text, "nan") {
+		return math.NaN(), nil
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("vrt value %q is invalid", text)
+	}
+	return value, nil
+}
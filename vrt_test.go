@@ -0,0 +1,305 @@
+package gdal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVRTBuilderMosaic(t *testing.T) {
+	left := createGTiffRasterDataset(t, "/vsimem/vrt_left.tif", 2, 2, 1)
+	defer left.Close()
+	right := createGTiffRasterDataset(t, "/vsimem/vrt_right.tif", 2, 2, 1)
+	defer right.Close()
+
+	builder := NewVRTBuilder(4, 2)
+	band := builder.AddBand(Float32)
+	if _, err := band.AddSimpleSource(left.RasterBand(1), Window{}, Window{XSize: 2, YSize: 2}); err != nil {
+		t.Fatalf("AddSimpleSource: %v", err)
+	}
+	source, err := band.AddComplexSource(right.RasterBand(1), Window{}, Window{XOff: 2, XSize: 2, YSize: 2})
+	if err != nil {
+		t.Fatalf("AddComplexSource: %v", err)
+	}
+	source.ScaleRatio = 10
+	source.ScaleOffset = 1
+
+	ds, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	defer ds.Close()
+
+	if ds.Driver().ShortName() != DriverNameVRT {
+		t.Errorf("driver = %s, want VRT", ds.Driver().ShortName())
+	}
+
+	values := make([]float32, 8)
+	if err := ds.RasterBand(1).IO(Read, 0, 0, 4, 2, values, 4, 2, 0, 0); err != nil {
+		t.Fatalf("RasterBand.IO(Read): %v", err)
+	}
+	want := []float32{0, 1, 1, 11, 2, 3, 21, 31}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Errorf("pixel %d = %v, want %v", i, values[i], want[i])
+		}
+	}
+
+	text, err := VRTXML(ds)
+	if err != nil {
+		t.Fatalf("VRTXML: %v", err)
+	}
+	if !strings.Contains(text, "/vsimem/vrt_right.tif") {
+		t.Errorf("VRTXML() does not reference the source:\n%s", text)
+	}
+}
+
+func TestVRTBuilderXMLRoundTrip(t *testing.T) {
+	noData := -1.0
+	shared := false
+	builder := NewVRTBuilder(10, 20)
+	builder.SRS = "EPSG:4326"
+	builder.SRSAxisMapping = []int{2, 1}
+	builder.SRSCoordinateEpoch = 2021.5
+	builder.GeoTransform = &[6]float64{1, 2, 0, 3, 0, -2}
+
+	band := builder.AddBand(Byte)
+	band.NoData = &noData
+	band.BlockXSize = 4
+	band.BlockYSize = 2
+	band.ExtraAttrs = map[string]string{"custom": "x"}
+	band.Sources = append(band.Sources, &VRTSource{
+		Kind:      VRTComplexSource,
+		Filename:  "a.tif",
+		Shared:    &shared,
+		Band:      2,
+		SrcWindow: Window{XSize: 5, YSize: 5},
+		DstWindow: Window{XOff: 1, YOff: 1, XSize: 5, YSize: 5},
+		NoData:    &noData,
+		LUT:       [][2]float64{{0, 10}, {1, 20}},
+	})
+	derived := builder.AddDerivedBand(Float32, "scale", map[string]string{"factor": "2"})
+	derived.Sources = append(derived.Sources, &VRTSource{Kind: VRTAveragedSource, Filename: "b.tif", Band: 1, RelativeToVRT: true})
+
+	text, err := builder.XML()
+	if err != nil {
+		t.Fatalf("XML: %v", err)
+	}
+	parsed, err := ParseVRT(text)
+	if err != nil {
+		t.Fatalf("ParseVRT: %v", err)
+	}
+	again, err := parsed.XML()
+	if err != nil {
+		t.Fatalf("XML after parse: %v", err)
+	}
+	if again != text {
+		t.Errorf("round trip mismatch:\n%s\n---\n%s", text, again)
+	}
+
+	if got := parsed.Bands[1].PixelFunctionArguments["factor"]; got != "2" {
+		t.Errorf("PixelFunctionArguments[factor] = %q, want 2", got)
+	}
+	if got := parsed.Bands[0].Sources[0].LUT; len(got) != 2 || got[1] != [2]float64{1, 20} {
+		t.Errorf("LUT = %v", got)
+	}
+	if got := parsed.SRSAxisMapping; len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Errorf("SRSAxisMapping = %v, want [2 1]", got)
+	}
+	if parsed.SRSCoordinateEpoch != 2021.5 {
+		t.Errorf("SRSCoordinateEpoch = %v, want 2021.5", parsed.SRSCoordinateEpoch)
+	}
+	if got := parsed.Bands[0]; got.BlockXSize != 4 || got.BlockYSize != 2 || got.ExtraAttrs["custom"] != "x" {
+		t.Errorf("band block size %dx%d, attributes %v", got.BlockXSize, got.BlockYSize, got.ExtraAttrs)
+	}
+	if got := parsed.Bands[0].Sources[0].Shared; got == nil || *got {
+		t.Errorf("Shared = %v, want false", got)
+	}
+	for _, want := range []string{`dataAxisToSRSAxisMapping="2,1"`, `blockXSize="4"`, `custom="x"`, `shared="0"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("XML() is missing %s:\n%s", want, text)
+		}
+	}
+}
+
+func TestParseVRTKeepsUnknownElements(t *testing.T) {
+	text := `<VRTDataset rasterXSize="2" rasterYSize="2">
+  <Metadata><MDI key="a">b</MDI></Metadata>
+  <VRTRasterBand dataType="Byte" band="1">
+    <ColorInterp>Gray</ColorInterp>
+    <Offset>1</Offset>
+    <SimpleSource>
+      <SourceFilename relativeToVRT="0">a.tif</SourceFilename>
+      <SourceBand>1</SourceBand>
+      <SourceProperties RasterXSize="2" RasterYSize="2" DataType="Byte"/>
+    </SimpleSource>
+  </VRTRasterBand>
+</VRTDataset>`
+	parsed, err := ParseVRT(text)
+	if err != nil {
+		t.Fatalf("ParseVRT: %v", err)
+	}
+	if !strings.Contains(parsed.ExtraXML, `<MDI key="a">b</MDI>`) {
+		t.Errorf("dataset ExtraXML = %q", parsed.ExtraXML)
+	}
+	if got := parsed.Bands[0].ExtraXML; !strings.Contains(got, "<ColorInterp>Gray</ColorInterp>") ||
+		!strings.Contains(got, "<Offset>1</Offset>") {
+		t.Errorf("band ExtraXML = %q", got)
+	}
+	if got := parsed.Bands[0].Sources[0].ExtraXML; !strings.Contains(got, "SourceProperties") {
+		t.Errorf("source ExtraXML = %q", got)
+	}
+
+	out, err := parsed.XML()
+	if err != nil {
+		t.Fatalf("XML: %v", err)
+	}
+	for _, want := range []string{"<ColorInterp>Gray</ColorInterp>", "<Offset>1</Offset>", `<MDI key="a">b</MDI>`, "SourceProperties"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("XML() lost %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestVRTBuilderRejectsInvalidInput(t *testing.T) {
+	if _, err := NewVRTBuilder(0, 1).XML(); err == nil {
+		t.Error("expected error for empty size")
+	}
+
+	builder := NewVRTBuilder(1, 1)
+	builder.AddBand(Byte).Sources = []*VRTSource{{Kind: VRTSimpleSource, ScaleRatio: 2}}
+	if _, err := builder.XML(); err == nil {
+		t.Error("expected error for scaling on a simple source")
+	}
+
+	mem := createMemoryRasterDataset(t, 1, 1, 1, Byte)
+	defer mem.Close()
+	if _, err := NewVRTBuilder(1, 1).AddBand(Byte).AddSimpleSource(mem.RasterBand(1), Window{}, Window{}); err == nil {
+		t.Error("expected error for memory source")
+	}
+
+	if _, err := ParseVRT(`<VRTDataset rasterXSize="1" rasterYSize="1" subClass="VRTWarpedDataset"/>`); err == nil {
+		t.Error("expected error for a warped VRT without warp options")
+	}
+	if _, err := ParseVRT(`<VRTDataset rasterXSize="1" rasterYSize="1" subClass="VRTPansharpenedDataset"/>`); err == nil {
+		t.Error("expected error for an unsupported subclass")
+	}
+	if _, err := ParseVRT(`<VRTDataset rasterXSize="1" rasterYSize="1">` +
+		`<VRTRasterBand dataType="Byte" band="1" subClass="VRTRawRasterBand"/></VRTDataset>`); err == nil {
+		t.Error("expected error for an unsupported band subclass")
+	}
+	if _, err := ParseVRT(`<VRTDataset rasterXSize="1" rasterYSize="1"><SRS unknown="1">EPSG:4326</SRS></VRTDataset>`); err == nil {
+		t.Error("expected error for an unknown SRS attribute")
+	}
+	builder = NewVRTBuilder(1, 1)
+	builder.Warp = &VRTWarpOptions{}
+	if _, err := builder.XML(); err == nil {
+		t.Error("expected error for a warped VRT without a source dataset")
+	}
+	if _, err := ParseVRT(`<VRTDataset rasterXSize="1" rasterYSize="1"><VRTRasterBand dataType="Byte" band="1">` +
+		`<KernelFilteredSource><SourceFilename>a.tif</SourceFilename></KernelFilteredSource></VRTRasterBand></VRTDataset>`); err == nil {
+		t.Error("expected error for an unsupported source")
+	}
+	if _, err := ParseVRT(`<VRTDataset rasterXSize="1" rasterYSize="1"><VRTRasterBand dataType="Byte" band="1">` +
+		`<SimpleSource><SourceFilename>a.tif</SourceFilename><SrcRect xOff="0.5" yOff="0" xSize="1" ySize="1"/></SimpleSource>` +
+		`</VRTRasterBand></VRTDataset>`); err == nil {
+		t.Error("expected error for a fractional source window")
+	}
+	builder = NewVRTBuilder(1, 1)
+	builder.ExtraXML = "<Metadata>"
+	if _, err := builder.XML(); err == nil {
+		t.Error("expected error for malformed extra XML")
+	}
+}
+
+func TestBuildWarpedVRT(t *testing.T) {
+	src := createGTiffRasterDataset(t, "/vsimem/vrt_warp_source.tif", 8, 8, 1)
+	defer src.Close()
+
+	srs := createSpatialReferenceFromEPSG(t, 3857)
+	defer srs.Destroy()
+	srcWKT, err := srs.ToWKT()
+	if err != nil {
+		t.Fatalf("ToWKT: %v", err)
+	}
+	if err := src.SetProjection(srcWKT); err != nil {
+		t.Fatalf("SetProjection: %v", err)
+	}
+	src.FlushCache()
+
+	wgs84 := createSpatialReferenceFromEPSG(t, 4326)
+	defer wgs84.Destroy()
+	wkt, err := wgs84.ToWKT()
+	if err != nil {
+		t.Fatalf("ToWKT: %v", err)
+	}
+
+	warped, err := BuildWarpedVRT(src, wkt, GRA_NearestNeighbour)
+	if err != nil {
+		t.Fatalf("BuildWarpedVRT: %v", err)
+	}
+	defer warped.Close()
+
+	text, err := VRTXML(warped)
+	if err != nil {
+		t.Fatalf("VRTXML: %v", err)
+	}
+	if !strings.Contains(text, "VRTWarpedDataset") {
+		t.Errorf("VRTXML() is not a warped VRT:\n%s", text)
+	}
+
+	builder, err := ParseVRT(text)
+	if err != nil {
+		t.Fatalf("ParseVRT: %v", err)
+	}
+	warp := builder.Warp
+	if warp == nil {
+		t.Fatal("ParseVRT() did not model the warp options")
+	}
+	if warp.SourceDataset != "/vsimem/vrt_warp_source.tif" || warp.Resampling != "NearestNeighbour" {
+		t.Errorf("warp source %q, resampling %q", warp.SourceDataset, warp.Resampling)
+	}
+	if len(warp.BandMapping) != 1 || warp.BandMapping[0] != (VRTWarpBand{Src: 1, Dst: 1}) {
+		t.Errorf("BandMapping = %+v", warp.BandMapping)
+	}
+	if !strings.Contains(warp.ExtraXML, "<Transformer>") {
+		t.Errorf("warp ExtraXML lost the transformer: %q", warp.ExtraXML)
+	}
+
+	warp.Resampling = "Bilinear"
+	builder.BlockXSize = 4
+	builder.BlockYSize = 4
+	out, err := builder.XML()
+	if err != nil {
+		t.Fatalf("XML: %v", err)
+	}
+	parsed, err := ParseVRT(out)
+	if err != nil {
+		t.Fatalf("ParseVRT after XML: %v", err)
+	}
+	again, err := parsed.XML()
+	if err != nil {
+		t.Fatalf("XML after parse: %v", err)
+	}
+	if again != out {
+		t.Errorf("round trip mismatch:\n%s\n---\n%s", out, again)
+	}
+
+	edited, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	defer edited.Close()
+	if x, y := edited.RasterBand(1).BlockSize(); x != 4 || y != 4 {
+		t.Errorf("BlockSize() = %dx%d, want 4x4", x, y)
+	}
+	if x, y := edited.RasterXSize(), edited.RasterYSize(); x != warped.RasterXSize() || y != warped.RasterYSize() {
+		t.Errorf("size = %dx%d, want %dx%d", x, y, warped.RasterXSize(), warped.RasterYSize())
+	}
+	text, err = VRTXML(edited)
+	if err != nil {
+		t.Fatalf("VRTXML: %v", err)
+	}
+	if !strings.Contains(text, "<ResampleAlg>Bilinear</ResampleAlg>") {
+		t.Errorf("VRTXML() lost the edited resampling:\n%s", text)
+	}
+}